@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenize splits a command line into words. Single quotes keep their
+// contents literally, double quotes allow backslash escapes, and a backslash
+// outside quotes escapes the next character. Quotes only start quoting at
+// the beginning of a word, so names like Farfetch'd can be typed as-is.
+func tokenize(line string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inToken := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inToken = true
+		case (r == '"' || r == '\'') && !inToken:
+			quote = r
+			inToken = true
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// FlagSpec describes a --flag accepted by a command.
+type FlagSpec struct {
+	Name        string
+	Kind        ArgKind
+	Description string
+	// Value names the flag's value in help, e.g. "name|id".
+	Value string
+	// Bool flags take no value.
+	Bool bool
+	// Repeatable flags may be given more than once.
+	Repeatable bool
+}
+
+// Args is a parsed command line: positional arguments plus flag values.
+type Args struct {
+	Positional []string
+	flags      map[string][]string
+}
+
+// NewArgs builds Args directly, mostly for tests.
+func NewArgs(positional []string, flags map[string][]string) Args {
+	return Args{Positional: positional, flags: flags}
+}
+
+// Arg returns the i'th positional argument, or "" if there isn't one.
+func (a Args) Arg(i int) string {
+	if i < len(a.Positional) {
+		return a.Positional[i]
+	}
+	return ""
+}
+
+// Has reports whether the flag was given.
+func (a Args) Has(name string) bool {
+	_, ok := a.flags[name]
+	return ok
+}
+
+// Flag returns the last value given for the flag, or "" if it wasn't given.
+func (a Args) Flag(name string) string {
+	values := a.flags[name]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// Flags returns every value given for a repeatable flag.
+func (a Args) Flags(name string) []string {
+	return a.flags[name]
+}
+
+func (a Args) Bool(name string) bool {
+	return a.Has(name)
+}
+
+// Int returns the flag's value as an integer, or fallback if it wasn't given.
+func (a Args) Int(name string, fallback int) (int, error) {
+	if !a.Has(name) {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(a.Flag(name))
+	if err != nil {
+		return 0, fmt.Errorf("--%s expects a number, got %q", name, a.Flag(name))
+	}
+	return n, nil
+}
+
+// normalize lowercases identifiers so they match PokeAPI names. Free-form
+// strings such as file paths are left alone.
+func (k ArgKind) normalize(value string) string {
+	if k == ArgString {
+		return value
+	}
+	return strings.ToLower(value)
+}
+
+// parseArgs splits tokens into flags and positional arguments. Flags may be
+// written --name value or --name=value and may appear anywhere; a bare --
+// ends flag parsing.
+func (cmd Command) parseArgs(tokens []string) (Args, error) {
+	args := Args{flags: map[string][]string{}}
+	var positional []string
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token == "--" {
+			positional = append(positional, tokens[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(token, "-") || token == "-" || isNumber(token) {
+			positional = append(positional, token)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(token, "-"), "=")
+		name = strings.ToLower(name)
+		spec, ok := cmd.flag(name)
+		if !ok {
+			return args, fmt.Errorf("unknown flag --%s\nusage: %s", name, cmd.UsageLine())
+		}
+		switch {
+		case spec.Bool && hasValue:
+			return args, fmt.Errorf("--%s does not take a value", name)
+		case spec.Bool:
+			value = "true"
+		case !hasValue:
+			if i+1 >= len(tokens) {
+				return args, fmt.Errorf("--%s expects a value", name)
+			}
+			i++
+			value = tokens[i]
+		}
+		if args.Has(name) && !spec.Repeatable {
+			return args, fmt.Errorf("--%s given more than once", name)
+		}
+		args.flags[name] = append(args.flags[name], spec.Kind.normalize(value))
+	}
+
+	// A Rest argument soaks up the remaining words, e.g. "catch Mr. Mime".
+	if n := len(cmd.Args); n > 0 && cmd.Args[n-1].Rest && len(positional) > n {
+		rest := strings.Join(positional[n-1:], " ")
+		positional = append(positional[:n-1], rest)
+	}
+	for i, value := range positional {
+		if spec, ok := cmd.argSpec(i); ok {
+			value = spec.Kind.normalize(value)
+		}
+		args.Positional = append(args.Positional, value)
+	}
+	return args, nil
+}
+
+func (cmd Command) flag(name string) (FlagSpec, bool) {
+	for _, spec := range cmd.Flags {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return FlagSpec{}, false
+}
+
+// argSpec returns the spec for the i'th positional argument, extending a
+// variadic last argument over the rest.
+func (cmd Command) argSpec(i int) (ArgSpec, bool) {
+	if i < len(cmd.Args) {
+		return cmd.Args[i], true
+	}
+	if n := len(cmd.Args); n > 0 && cmd.Args[n-1].Variadic {
+		return cmd.Args[n-1], true
+	}
+	return ArgSpec{}, false
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
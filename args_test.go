@@ -0,0 +1,106 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{" hello  world ", []string{"hello", "world"}},
+		{`save "My Dex.json"`, []string{"save", "My Dex.json"}},
+		{`save 'My "Dex".json'`, []string{"save", `My "Dex".json`}},
+		{`save "say \"hi\" \\ now"`, []string{"save", `say "hi" \ now`}},
+		{`save My\ Dex.json`, []string{"save", "My Dex.json"}},
+		{`catch "" pikachu`, []string{"catch", "", "pikachu"}},
+		{`catch Farfetch'd`, []string{"catch", "Farfetch'd"}},
+		{`catch mr"."mime`, []string{"catch", `mr"."mime`}},
+		{"", nil},
+	}
+	for _, c := range cases {
+		actual, err := tokenize(c.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", c.input, err)
+			continue
+		}
+		if !slices.Equal(actual, c.expected) {
+			t.Errorf("%q: expected %q, got %q", c.input, c.expected, actual)
+		}
+	}
+
+	for _, bad := range []string{`save "My Dex.json`, `save 'oops`, `save oops\`} {
+		if _, err := tokenize(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	cmd := Command{
+		Name: "explore",
+		Args: []ArgSpec{
+			{Name: "area", Kind: ArgLocation},
+			{Name: "file", Kind: ArgString, Optional: true},
+		},
+		Flags: []FlagSpec{
+			{Name: "version", Kind: ArgKeyword},
+			{Name: "details", Bool: true},
+			{Name: "tag", Kind: ArgString, Repeatable: true},
+			{Name: "limit"},
+		},
+	}
+
+	args, err := cmd.parseArgs([]string{"--version", "Red", "Canalave-City-Area", "--details", "--tag=One", "--tag", "Two", "--limit", "-5", "Out File.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(args.Positional, []string{"canalave-city-area", "Out File.txt"}) {
+		t.Errorf("unexpected positional args %q", args.Positional)
+	}
+	if args.Flag("version") != "red" {
+		t.Errorf("expected version to be lowercased, got %q", args.Flag("version"))
+	}
+	if !args.Bool("details") || args.Bool("missing") {
+		t.Errorf("unexpected bool flags %v", args.flags)
+	}
+	if !slices.Equal(args.Flags("tag"), []string{"One", "Two"}) {
+		t.Errorf("expected string flags to keep their case, got %q", args.Flags("tag"))
+	}
+	if n, err := args.Int("limit", 0); err != nil || n != -5 {
+		t.Errorf("expected limit -5, got %d %v", n, err)
+	}
+	if n, err := args.Int("offset", 7); err != nil || n != 7 {
+		t.Errorf("expected fallback 7, got %d %v", n, err)
+	}
+
+	catch := Command{Name: "catch", Args: []ArgSpec{{Name: "pokemon", Kind: ArgPokemon, Rest: true}}}
+	args, err = catch.parseArgs([]string{"Mr.", "Mime"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(args.Positional, []string{"mr. mime"}) {
+		t.Errorf("expected the rest of the line in one argument, got %q", args.Positional)
+	}
+
+	args, err = cmd.parseArgs([]string{"--", "--details"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(args.Positional, []string{"--details"}) || args.Bool("details") {
+		t.Errorf("expected -- to end flag parsing, got %+v", args)
+	}
+
+	for _, bad := range [][]string{
+		{"--unknown"},
+		{"--version"},
+		{"--details=yes"},
+		{"--version", "red", "--version", "blue"},
+	} {
+		if _, err := cmd.parseArgs(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,730 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shamsup/pokedexcli/internal/pokeapitest"
+	"github.com/shamsup/pokedexcli/internal/termimg"
+	"github.com/shamsup/pokedexcli/internal/termout"
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+	"github.com/shamsup/pokedexcli/pokedex/sqlitestore"
+)
+
+// testREPL runs command lines against the fixture server and captures
+// their output.
+type testREPL struct {
+	t        *testing.T
+	c        *Config
+	out      *bytes.Buffer
+	commands *Registry
+}
+
+func newTestREPL(t *testing.T) *testREPL {
+	t.Helper()
+	pokeapitest.NewServer(t)
+	out := &bytes.Buffer{}
+	alwaysCatch := pokedex.CatchPolicyFunc(func(pokeapi.PokemonDetails) bool { return true })
+	c := &Config{
+		Pokedex: pokedex.NewPokedex(pokedex.WithCatchPolicy(alwaysCatch)),
+		Out:     termout.New(out),
+	}
+	r := NewRegistry()
+	registerCommands(r, c)
+	return &testREPL{t: t, c: c, out: out, commands: r}
+}
+
+// exec runs line and returns its output and error.
+func (r *testREPL) exec(line string) (string, error) {
+	r.t.Helper()
+	r.out.Reset()
+	words, err := tokenize(line)
+	if err != nil {
+		return "", err
+	}
+	err = r.commands.Dispatch(words[0], words[1:])
+	return r.out.String(), err
+}
+
+// run runs line and fails the test if it returns an error.
+func (r *testREPL) run(line string) string {
+	r.t.Helper()
+	out, err := r.exec(line)
+	if err != nil {
+		r.t.Fatalf("%s: unexpected error: %v", line, err)
+	}
+	return out
+}
+
+func TestMapAndMapBack(t *testing.T) {
+	r := newTestREPL(t)
+
+	if got := r.run("mapb"); !strings.Contains(got, "you're on the first page") {
+		t.Errorf("expected first page message, got %q", got)
+	}
+
+	first := r.run("map")
+	if !strings.HasPrefix(first, "canalave-city-area\n") || strings.Count(first, "\n") != 20 {
+		t.Errorf("expected the first 20 areas, got %q", first)
+	}
+
+	second := r.run("map")
+	if !strings.HasPrefix(second, "mt-coronet-1f-route-216\n") {
+		t.Errorf("expected the second page, got %q", second)
+	}
+
+	back := r.run("mapb")
+	if back != first {
+		t.Errorf("expected mapb to return to the first page, got %q", back)
+	}
+}
+
+func TestExplore(t *testing.T) {
+	r := newTestREPL(t)
+
+	got := r.run("explore Canalave-City-Area")
+	for _, want := range []string{"Exploring canalave-city-area...", "Found Pokemon:", "  - tentacool\n", "  - magikarp\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got %q", want, got)
+		}
+	}
+
+	if _, err := r.exec("explore nowhere"); err == nil {
+		t.Errorf("expected an error exploring an unknown area")
+	}
+}
+
+func TestCatchAndInspect(t *testing.T) {
+	r := newTestREPL(t)
+
+	if got := r.run("inspect pikachu"); !strings.Contains(got, "you have no caught that pokemon") {
+		t.Errorf("expected pikachu to be uncaught, got %q", got)
+	}
+
+	got := r.run("CATCH Pikachu")
+	if got != "Throwing a Pokeball at pikachu...\npikachu was caught!\n" {
+		t.Errorf("unexpected catch output %q", got)
+	}
+
+	got = r.run("inspect pikachu")
+	for _, want := range []string{"Name: pikachu", "Height: 4", "Weight: 60", "  Speed    90 ████████▍\n", "  Total   320\n", "EV yield: 2 Speed\n", "  - electric"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got %q", want, got)
+		}
+	}
+
+	if _, err := r.exec("catch missingno"); err == nil {
+		t.Errorf("expected an error catching an unknown pokemon")
+	}
+}
+
+func TestPokedexCommand(t *testing.T) {
+	r := newTestREPL(t)
+	r.run("catch pikachu")
+	r.run("catch charmander")
+
+	got := r.run("pokedex --sort id")
+	if got != " - #004 charmander (fire) BST 309\n - #025 pikachu (electric) BST 320\n" {
+		t.Errorf("unexpected pokedex output %q", got)
+	}
+	if got := r.run("dex --type=FIRE"); !strings.Contains(got, "charmander") || strings.Contains(got, "pikachu") {
+		t.Errorf("expected only charmander, got %q", got)
+	}
+	if _, err := r.exec("pokedex --colour red"); err == nil || !strings.Contains(err.Error(), "unknown flag --colour") {
+		t.Errorf("expected an unknown flag error, got %v", err)
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	r := newTestREPL(t)
+
+	cases := []struct {
+		line string
+		want string
+	}{
+		{"explore canalave", `no location area named "canalave". Did you mean canalave-city-area?`},
+		{"catch pikchu", `no pokemon named "pikchu". Did you mean pichu, pikachu?`},
+		{"catch zzzzzz", `no pokemon named "zzzzzz".`},
+	}
+	for _, c := range cases {
+		_, err := r.exec(c.line)
+		if err == nil || err.Error() != c.want {
+			t.Errorf("%s: expected %q, got %v", c.line, c.want, err)
+		}
+	}
+
+	r.run("catch pikachu")
+	if got := r.run("inspect pikachuu"); !strings.Contains(got, "Did you mean pikachu?") {
+		t.Errorf("expected inspect to suggest a caught pokemon, got %q", got)
+	}
+
+	if got := didYouMean("mpa", r.commands.Names()); got != " Did you mean map?" {
+		t.Errorf("expected a command suggestion, got %q", got)
+	}
+}
+
+func TestCatchResolvesNames(t *testing.T) {
+	r := newTestREPL(t)
+
+	cases := []struct {
+		line string
+		name string
+	}{
+		{"catch 25", "pikachu"},
+		{"catch Mr. Mime", "mr-mime"},
+		{"catch Farfetch'd", "farfetchd"},
+		{"catch コイキング", "magikarp"},
+	}
+	for _, c := range cases {
+		if got := r.run(c.line); !strings.Contains(got, c.name+" was caught!") {
+			t.Errorf("%s: expected %s to be caught, got %q", c.line, c.name, got)
+		}
+	}
+	if got := r.run("inspect 25"); !strings.Contains(got, "Name: pikachu") {
+		t.Errorf("expected inspect to accept a dex number, got %q", got)
+	}
+	if got := r.run("pokedex --sort id"); strings.Count(got, "\n") != 4 {
+		t.Errorf("expected four distinct pokemon, got %q", got)
+	}
+}
+
+func TestLang(t *testing.T) {
+	r := newTestREPL(t)
+
+	if got := r.run("lang"); !strings.Contains(got, "Showing API names") {
+		t.Errorf("expected API names by default, got %q", got)
+	}
+	if _, err := r.exec("lang klingon"); err == nil {
+		t.Errorf("expected an unknown language to be rejected")
+	}
+	if got := r.run("lang JA-HRKT"); got != "Showing names in ja-Hrkt.\n" {
+		t.Errorf("expected the API's spelling of the code, got %q", got)
+	}
+
+	if got := r.run("explore canalave-city-area"); !strings.Contains(got, "Exploring ミオシティ...") || !strings.Contains(got, "  - コイキング\n") {
+		t.Errorf("expected localized area and pokemon names, got %q", got)
+	}
+	if got := r.run("map"); !strings.HasPrefix(got, "ミオシティ (canalave-city-area)\n") {
+		t.Errorf("expected localized map names alongside API names, got %q", got)
+	}
+	if got := r.run("catch pikachu"); !strings.Contains(got, "ピカチュウ was caught!") {
+		t.Errorf("expected a localized catch message, got %q", got)
+	}
+	if got := r.run("inspect pikachu"); !strings.Contains(got, "Name: ピカチュウ") || !strings.Contains(got, "  - でんき\n") {
+		t.Errorf("expected localized inspect output, got %q", got)
+	}
+
+	r.run("lang fr")
+	if got := r.run("pokedex"); !strings.Contains(got, "Pikachu (Électrik)") {
+		t.Errorf("expected French pokedex output, got %q", got)
+	}
+
+	r.run("lang off")
+	if got := r.run("pokedex"); !strings.Contains(got, "pikachu (electric)") {
+		t.Errorf("expected API names after lang off, got %q", got)
+	}
+}
+
+func TestExploreEncounters(t *testing.T) {
+	r := newTestREPL(t)
+
+	got := r.run("explore valley-windworks-area --method walk")
+	for _, want := range []string{
+		"POKEMON    CHANCE  LEVELS  CONDITIONS           VERSIONS",
+		"shinx      40%     5-6     swarm-no, radar-off  diamond, pearl, platinum",
+		"buizel     10%     6       -                    diamond, pearl",
+		"buizel     5%      7       -                    platinum",
+		"hoothoot   10%     5-6     time-night",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "magikarp") {
+		t.Errorf("expected only walking encounters, got\n%s", got)
+	}
+
+	got = r.run("explore valley-windworks-area --version pearl --details")
+	for _, want := range []string{"walk (rate 10):", "old-rod (rate 25):", "surf (rate 10):", "  magikarp  100%"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "pachirisu") || strings.Contains(got, "VERSIONS") {
+		t.Errorf("expected only pearl encounters without a versions column, got\n%s", got)
+	}
+
+	if _, err := r.exec("explore valley-windworks-area --version platnum"); err == nil || !strings.Contains(err.Error(), "Did you mean platinum?") {
+		t.Errorf("expected a version suggestion, got %v", err)
+	}
+	if _, err := r.exec("explore canalave-city-area --method walk"); err == nil {
+		t.Errorf("expected an error for a method the area doesn't have")
+	}
+}
+
+func TestRegionNavigation(t *testing.T) {
+	r := newTestREPL(t)
+
+	if got := r.run("regions"); got != "kanto\njohto\nhoenn\nsinnoh\n" {
+		t.Errorf("expected every region, got %q", got)
+	}
+
+	got := r.run("region kanto")
+	for _, want := range []string{"Locations in kanto:", "  - pallet-town\n", "  - viridian-forest\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got %q", want, got)
+		}
+	}
+	if _, err := r.exec("region kantoo"); err == nil || !strings.Contains(err.Error(), "Did you mean kanto?") {
+		t.Errorf("expected a region suggestion, got %v", err)
+	}
+
+	if got := r.run("areas pallet-town"); got != "Areas in pallet-town:\n  - pallet-town-area\n" {
+		t.Errorf("unexpected areas output %q", got)
+	}
+	if got := r.run("areas viridian-forest"); !strings.Contains(got, "has no areas") {
+		t.Errorf("expected no areas, got %q", got)
+	}
+	if _, err := r.exec("areas palet-town"); err == nil || !strings.Contains(err.Error(), "Did you mean pallet-town?") {
+		t.Errorf("expected a location suggestion, got %v", err)
+	}
+
+	r.run("lang fr")
+	if got := r.run("areas pallet-town"); !strings.HasPrefix(got, "Areas in Bourg Palette:\n") {
+		t.Errorf("expected a localized location name, got %q", got)
+	}
+}
+
+func TestMapRegion(t *testing.T) {
+	r := newTestREPL(t)
+
+	if got := r.run("map --region sinnoh"); !strings.HasPrefix(got, "canalave-city-area\neterna-city-area\nvalley-windworks-area\nmt-coronet-1f-route-207\n") || strings.Count(got, "\n") != 8 {
+		t.Errorf("expected sinnoh's areas, got %q", got)
+	}
+	if got := r.run("map"); got != "you're on the last page\n" {
+		t.Errorf("expected the region to fit on one page, got %q", got)
+	}
+	if got := r.run("mapb"); got != "you're on the first page\n" {
+		t.Errorf("expected to be on the first page, got %q", got)
+	}
+	if got := r.run("map --region kanto"); got != "pallet-town-area\nkanto-route-1-area\nviridian-city-area\n" {
+		t.Errorf("expected kanto's areas, got %q", got)
+	}
+	if _, err := r.exec("map --region orre"); err == nil {
+		t.Errorf("expected an error for an unknown region")
+	}
+	if got := r.run("map --region all"); strings.Count(got, "\n") != 20 || !strings.HasPrefix(got, "canalave-city-area\n") {
+		t.Errorf("expected the first page of every area, got %q", got)
+	}
+}
+
+func TestWhere(t *testing.T) {
+	r := newTestREPL(t)
+
+	got := r.run("where magikarp")
+	lines := strings.Split(got, "\n")
+	if len(lines) < 3 || !strings.HasPrefix(lines[0], "magikarp can be found in:") {
+		t.Fatalf("unexpected where output %q", got)
+	}
+	for i, want := range []string{
+		"  #  AREA                   METHOD     CHANCE  LEVELS  CONDITIONS  VERSIONS",
+		"  1  canalave-city-area     old-rod    100%    3-15    -           diamond, pearl, platinum",
+		"  2  eterna-city-area       old-rod    100%    3-15    -           diamond, pearl, platinum",
+		"  3  valley-windworks-area  old-rod    100%    3-15    -           diamond, pearl, platinum",
+		"  4  canalave-city-area     good-rod   60%     10-25   -           diamond, pearl, platinum",
+	} {
+		if lines[i+1] != want {
+			t.Errorf("line %d: expected %q, got %q", i+1, want, lines[i+1])
+		}
+	}
+
+	got = r.run("explore #2")
+	if !strings.Contains(got, "Exploring eterna-city-area...") {
+		t.Errorf("expected explore #2 to use the where listing, got %q", got)
+	}
+	if _, err := r.exec("explore #99"); err == nil {
+		t.Errorf("expected an error for a number outside the listing")
+	}
+
+	got = r.run("where 25 --version yellow")
+	if !strings.Contains(got, "viridian-forest-area") || strings.Contains(got, "trophy-garden-area") || strings.Contains(got, "VERSIONS") {
+		t.Errorf("expected only yellow's areas, got %q", got)
+	}
+	if got := r.run("where charmander"); got != "charmander can't be found in the wild\n" {
+		t.Errorf("expected no wild encounters, got %q", got)
+	}
+}
+
+func TestProfileAndVersion(t *testing.T) {
+	r := newTestREPL(t)
+	r.c.ProfilePath = filepath.Join(t.TempDir(), profileFileName)
+
+	if got := r.run("profile --name Dawn"); !strings.Contains(got, "Trainer: Dawn\n") || !strings.Contains(got, "Game: all versions") {
+		t.Errorf("unexpected profile output %q", got)
+	}
+	if _, err := r.exec("version platnum"); err == nil || !strings.Contains(err.Error(), "Did you mean platinum?") {
+		t.Errorf("expected a version suggestion, got %v", err)
+	}
+	if got := r.run("version platinum"); got != "Game: platinum (platinum, generation 4, sinnoh)\n" {
+		t.Errorf("unexpected version output %q", got)
+	}
+	if saved, err := loadProfile(r.c.ProfilePath); err != nil || saved != (Profile{Name: "Dawn", Version: "platinum"}) {
+		t.Errorf("expected the profile to be saved, got %+v, %v", saved, err)
+	}
+
+	// Buizel is found here in every game, but only platinum has Pachirisu.
+	got := r.run("explore valley-windworks-area --method walk")
+	if strings.Contains(got, "VERSIONS") || !strings.Contains(got, "pachirisu") || strings.Contains(got, "diamond") {
+		t.Errorf("expected only platinum encounters, got\n%s", got)
+	}
+	r.run("version pearl")
+	if got := r.run("explore valley-windworks-area"); strings.Contains(got, "pachirisu") {
+		t.Errorf("expected no pachirisu in pearl, got %q", got)
+	}
+	if got := r.run("explore valley-windworks-area --version all --method walk"); !strings.Contains(got, "pachirisu") {
+		t.Errorf("expected --version all to show every game, got %q", got)
+	}
+	if got := r.run("where pikachu"); !strings.Contains(got, "trophy-garden-area") || strings.Contains(got, "viridian-forest-area") {
+		t.Errorf("expected where to use the selected version, got %q", got)
+	}
+
+	if _, err := r.exec("catch mr-mime"); err == nil || !strings.Contains(err.Error(), "isn't in pearl") {
+		t.Errorf("expected mr-mime to be uncatchable in pearl, got %v", err)
+	}
+	r.run("catch pikachu")
+	if got := r.run("inspect pikachu --sprite front"); !strings.Contains(got, "/sprites/pokemon/versions/generation-iv/diamond-pearl/25.png\n") {
+		t.Errorf("expected a diamond-pearl sprite, got %q", got)
+	}
+
+	r.run("version red")
+	r.run("catch mr-mime")
+	if got := r.run("pokedex"); !strings.Contains(got, "mr-mime (psychic)") {
+		t.Errorf("expected mr-mime's generation I types, got %q", got)
+	}
+	r.run("version all")
+	if got := r.run("pokedex"); !strings.Contains(got, "mr-mime (psychic/fairy)") {
+		t.Errorf("expected mr-mime's current types, got %q", got)
+	}
+}
+
+func TestMoves(t *testing.T) {
+	r := newTestREPL(t)
+
+	got := r.run("moves pikachu")
+	want := "Moves pikachu learns in platinum:\n" +
+		"  LEVEL  MOVE           METHOD\n" +
+		"  1      thunder-shock  level-up\n" +
+		"  10     quick-attack   level-up\n" +
+		"  -      thunderbolt    machine\n"
+	if got != want {
+		t.Errorf("expected the latest game's moves:\n%s\ngot:\n%s", want, got)
+	}
+
+	got = r.run("moves 25 --version blue --method level-up")
+	if !strings.Contains(got, "learns in red-blue") || !strings.Contains(got, "16     quick-attack") || strings.Contains(got, "thunderbolt") {
+		t.Errorf("expected red-blue level-up moves, got %q", got)
+	}
+
+	r.run("lang fr")
+	if got := r.run("moves pikachu --method machine"); !strings.Contains(got, "Tonnerre") {
+		t.Errorf("expected localized move names, got %q", got)
+	}
+}
+
+func TestInspectSprite(t *testing.T) {
+	r := newTestREPL(t)
+	r.c.Graphics = termimg.HalfBlocks
+	r.run("catch pikachu")
+
+	if got := r.run("inspect pikachu"); strings.Contains(got, "▀") || strings.Contains(got, "Sprite:") {
+		t.Errorf("expected no sprite without --sprite, got %q", got)
+	}
+	got := r.run("inspect pikachu --sprite shiny")
+	if !strings.Contains(got, "/sprites/pokemon/shiny/25.png\n") || !strings.Contains(got, "\x1b[38;2;247;168;34m") {
+		t.Errorf("expected the shiny sprite to be drawn, got %q", got)
+	}
+	if got := r.run("inspect pikachu --sprite gen1-red-blue"); !strings.Contains(got, "\x1b[38;2;168;168;168m") {
+		t.Errorf("expected the red-blue sprite to be drawn, got %q", got)
+	}
+	got = r.run("inspect pikachu --sprite gen1-red-blue --sprite shiny")
+	if !strings.Contains(got, "No shiny front sprite for pikachu in red-blue; showing the latest one.\n") || !strings.Contains(got, "/shiny/25.png\n") {
+		t.Errorf("expected a note about the missing shiny sprite, got %q", got)
+	}
+	if got := r.run("inspect pikachu --sprite back --sprite shiny"); !strings.Contains(got, "The sprite's image is missing.\n") {
+		t.Errorf("expected a note about the missing sprite, got %q", got)
+	}
+	if _, err := r.exec("inspect pikachu --sprite sideways"); err == nil || !strings.Contains(err.Error(), "unknown sprite") {
+		t.Errorf("expected an unknown sprite error, got %v", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	r := newTestREPL(t)
+	r.run("catch pikachu")
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "dex.csv")
+	if got := r.run("export " + file); got != "Exported 1 Pokemon to "+file+"\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil || !strings.Contains(string(data), "\n25,pikachu,electric,35,55,40,50,50,90,320,") {
+		t.Errorf("unexpected csv %q, %v", data, err)
+	}
+
+	if _, err := r.exec("export " + filepath.Join(dir, "dex.txt")); err == nil || !strings.Contains(err.Error(), "--format csv|json|md") {
+		t.Errorf("expected an error for an unknown extension, got %v", err)
+	}
+	if _, err := r.exec("export " + filepath.Join(dir, "dex.txt") + " --format jsno"); err == nil || !strings.Contains(err.Error(), "Did you mean json?") {
+		t.Errorf("expected a format suggestion, got %v", err)
+	}
+	r.run("export " + filepath.Join(dir, "dex.txt") + " --format md")
+	if data, _ := os.ReadFile(filepath.Join(dir, "dex.txt")); !strings.HasPrefix(string(data), "| id | name |") {
+		t.Errorf("expected a markdown table, got %q", data)
+	}
+}
+
+func TestImport(t *testing.T) {
+	r := newTestREPL(t)
+	file := filepath.Join(t.TempDir(), "team.json")
+	data := `{"format_version": 1, "pokemon": [
+		{"id": 25, "name": "pikachu", "caught_at": "2024-08-01T12:00:00Z", "attempts": 2},
+		{"name": "Mr. Mime", "caught_at": "2024-08-02T12:00:00Z", "attempts": 1},
+		{"name": "missingno"},
+		{"name": ""}
+	]}`
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r.run("catch charmander")
+	r.run("catch pikachu")
+
+	got := r.run("import " + file + " --strategy union")
+	want := "Imported " + file + ":\n" +
+		"  Added 1: mr-mime\n" +
+		"  Conflicts 1 (combined): pikachu\n" +
+		"  Skipped 2:\n" +
+		"   - (record 4): no name\n" +
+		"   - missingno: no Pokemon by that name\n"
+	if got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+	if got := r.run("pokedex"); !strings.Contains(got, "mr-mime") || !strings.Contains(got, "charmander") {
+		t.Errorf("expected the import to be merged, got %q", got)
+	}
+
+	if err := os.WriteFile(file, []byte(`{"format_version": 9, "pokemon": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.exec("import " + file); err == nil || !strings.Contains(err.Error(), "unsupported format version 9") {
+		t.Errorf("expected a format version error, got %v", err)
+	}
+	if _, err := r.exec("import " + file + " --strategy merge"); err == nil {
+		t.Errorf("expected an unknown strategy error")
+	}
+
+	// Another trainer's saved Pokedex is recognized by its contents.
+	saved := filepath.Join(t.TempDir(), "barry")
+	store, err := sqlitestore.Open(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	always := pokedex.CatchPolicyFunc(func(pokeapi.PokemonDetails) bool { return true })
+	if _, _, err := pokedex.NewPokedex(pokedex.WithStore(store), pokedex.WithCatchPolicy(always)).CatchPokemon("magikarp"); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+	if got := r.run("import " + saved); !strings.Contains(got, "  Added 1: magikarp\n") {
+		t.Errorf("expected magikarp to be imported from the saved Pokedex, got %q", got)
+	}
+}
+
+// mapCache is an in-memory pokeapi.ResponseCache standing in for the
+// database. Its responses are always fresh.
+type mapCache struct {
+	mu        sync.Mutex
+	responses map[string][]byte
+}
+
+func (c *mapCache) Get(url string) ([]byte, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	body, ok := c.responses[url]
+	return body, time.Now(), ok
+}
+
+func (c *mapCache) Add(url string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses[url] = body
+}
+
+func TestPrefetch(t *testing.T) {
+	r := newTestREPL(t)
+	if _, err := r.exec("prefetch locations"); err == nil || !strings.Contains(err.Error(), "--db") {
+		t.Errorf("expected prefetch to need a database, got %v", err)
+	}
+
+	cache := &mapCache{responses: map[string][]byte{}}
+	previous := pokeapi.SetPersistentCache(cache)
+	t.Cleanup(func() { pokeapi.SetPersistentCache(previous) })
+
+	// Only some location areas have fixtures; the rest count as not found.
+	want := "region: 4/4\nlocation: 8/8\nlocation-area: 40/40\nFetched 15, 0 already saved, 37 not found\n"
+	if got := r.run("prefetch locations --concurrency 4"); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+	saved := false
+	for url := range cache.responses {
+		saved = saved || strings.HasSuffix(url, "/api/v2/region/kanto")
+	}
+	if !saved {
+		t.Errorf("expected kanto to be saved under the URL GetRegion uses")
+	}
+	// Running it again resumes: only the missing areas are requested.
+	if got := r.run("prefetch locations"); !strings.HasSuffix(got, "Fetched 0, 15 already saved, 37 not found\n") {
+		t.Errorf("expected everything to be saved already, got %q", got)
+	}
+
+	if _, err := r.exec("prefetch pokemn"); err == nil || !strings.Contains(err.Error(), "Did you mean pokemon?") {
+		t.Errorf("expected a suggestion, got %v", err)
+	}
+	if _, err := r.exec("prefetch all --concurrency 0"); err == nil {
+		t.Errorf("expected an error for --concurrency 0")
+	}
+
+	bar := &progressBar{label: "pokemon", total: 4, done: 2}
+	if got := bar.line(); got != "pokemon [###############---------------]  50% 2/4 ETA --" {
+		t.Errorf("unexpected progress bar %q", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	r := newTestREPL(t)
+	r.run("catch pikachu")
+	r.run("catch charmander")
+
+	want := "" +
+		"              pikachu                         charmander\n" +
+		"Types         electric                        fire\n" +
+		"HP            35                              39*\n" +
+		"Attack        55*                             52\n" +
+		"Defense       40                              43*\n" +
+		"Sp. Atk       50                              60*\n" +
+		"Sp. Def       50                              50\n" +
+		"Speed         90*                             65\n" +
+		"Total         320*                            309\n" +
+		"Height        0.4 m                           0.6 m*\n" +
+		"Weight        6 kg                            8.5 kg*\n" +
+		"Abilities     static, lightning-rod (hidden)  blaze, solar-power (hidden)\n" +
+		"Damage taken\n" +
+		"  bug         x1                              x0.5*\n" +
+		"  electric    x0.5*                           x1\n" +
+		"  fairy       x1                              x0.5*\n" +
+		"  fire        x1                              x0.5*\n" +
+		"  flying      x0.5*                           x1\n" +
+		"  grass       x1                              x0.5*\n" +
+		"  ground      x2                              x2\n" +
+		"  ice         x1                              x0.5*\n" +
+		"  rock        x1*                             x2\n" +
+		"  steel       x0.5                            x0.5\n" +
+		"  water       x1*                             x2\n"
+	if got := r.run("compare pikachu charmander"); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	if _, err := r.exec("compare pikachu magikarp"); err == nil || !strings.Contains(err.Error(), "--any") {
+		t.Errorf("expected uncaught Pokemon to need --any, got %v", err)
+	}
+	if got := r.run("compare pikachu charmander 129 --any"); !strings.Contains(got, "magikarp") || !strings.Contains(got, "10 kg*") {
+		t.Errorf("expected magikarp to be fetched, got %q", got)
+	}
+	if _, err := r.exec("compare pikachu"); err == nil {
+		t.Errorf("expected an error comparing one Pokemon")
+	}
+	if _, err := r.exec("compare pikachu pikachuu --any"); err == nil || !strings.Contains(err.Error(), "Did you mean pikachu?") {
+		t.Errorf("expected a suggestion, got %v", err)
+	}
+
+	r.c.Out.Color = true
+	if got := r.run("compare pikachu charmander"); !strings.Contains(got, "Speed         \x1b[1;32m90\x1b[0m                              65\n") {
+		t.Errorf("expected the faster Pokemon to be highlighted in color, got %q", got)
+	}
+}
+
+func TestInspectStats(t *testing.T) {
+	r := newTestREPL(t)
+	cache := &mapCache{responses: map[string][]byte{}}
+	previous := pokeapi.SetPersistentCache(cache)
+	t.Cleanup(func() { pokeapi.SetPersistentCache(previous) })
+
+	r.run("catch pikachu")
+	if got := r.run("inspect pikachu"); strings.Contains(got, "%") {
+		t.Errorf("expected no percentiles before the stats are ranked, got %q", got)
+	}
+	if got := r.run("prefetch pokemon"); !strings.Contains(got, "Ranked the base stats of 6 species\n") {
+		t.Errorf("expected prefetch to rank the stats, got %q", got)
+	}
+	got := r.run("inspect pikachu")
+	for _, want := range []string{
+		"  Defense  40 ███▊                       0%\n",
+		"  Speed    90 ████████▍                 50%\n",
+		"  Total   320                           33%\n",
+		"Percentiles are the share of 6 species with a lower base stat.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+
+	r.c.Out.Color = true
+	if got := r.run("inspect pikachu"); !strings.Contains(got, "\x1b[92m████████▍               \x1b[0m  50%") {
+		t.Errorf("expected a colored speed bar, got %q", got)
+	}
+}
+
+func TestStyledOutput(t *testing.T) {
+	r := newTestREPL(t)
+	r.c.Out.Color = true
+
+	if got := r.run("catch pikachu"); !strings.Contains(got, "\x1b[32mpikachu was caught!\x1b[0m\n") {
+		t.Errorf("expected a green catch message, got %q", got)
+	}
+	if got := r.run("inspect pikachu"); !strings.Contains(got, "  - \x1b[38;2;248;208;48melectric\x1b[0m\n") {
+		t.Errorf("expected electric in its type color, got %q", got)
+	}
+	if got := r.run("pokedex"); !strings.Contains(got, "(\x1b[38;2;248;208;48melectric\x1b[0m)") {
+		t.Errorf("expected electric in its type color, got %q", got)
+	}
+
+	r.c.Out.Width = 80
+	got := r.run("map")
+	if lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n"); len(lines) >= 20 || !strings.HasPrefix(lines[0], "canalave-city-area  ") {
+		t.Errorf("expected the areas in columns, got %q", got)
+	}
+}
+
+func TestExit(t *testing.T) {
+	r := newTestREPL(t)
+	r.c.Out.TTY, r.c.Out.Height, r.c.Out.Pager = true, 24, "cat"
+
+	// Output is buffered for the pager while the command runs, so exit must
+	// return rather than end the process.
+	err := r.c.Out.Page(func() error {
+		_, err := r.exec("exit")
+		return err
+	})
+	if !errors.Is(err, errExit) {
+		t.Errorf("expected errExit, got %v", err)
+	}
+	if got := r.out.String(); got != "Closing the Pokedex... Goodbye!\n" {
+		t.Errorf("expected a goodbye, got %q", got)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shamsup/pokedexcli/internal/termout"
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+)
+
+// compareStats are the stat rows of compare, in game order.
+var compareStats = []struct{ name, label string }{
+	{"hp", "HP"},
+	{"attack", "Attack"},
+	{"defense", "Defense"},
+	{"special-attack", "Sp. Atk"},
+	{"special-defense", "Sp. Def"},
+	{"speed", "Speed"},
+}
+
+// compareCell is one value in the comparison table. Best cells are
+// highlighted.
+type compareCell struct {
+	text string
+	best bool
+}
+
+type compareRow struct {
+	label string
+	cells []compareCell
+}
+
+// textRow is a row of values that aren't compared.
+func textRow(label string, values []string) compareRow {
+	row := compareRow{label: label}
+	for _, value := range values {
+		row.cells = append(row.cells, compareCell{text: value})
+	}
+	return row
+}
+
+// numberRow highlights the highest values, or the lowest if lowerIsBetter,
+// unless they're all the same.
+func numberRow(label string, values []float64, lowerIsBetter bool, format func(float64) string) compareRow {
+	row := compareRow{label: label}
+	best := slices.Max(values)
+	if lowerIsBetter {
+		best = slices.Min(values)
+	}
+	tied := slices.Min(values) == slices.Max(values)
+	for _, value := range values {
+		row.cells = append(row.cells, compareCell{text: format(value), best: !tied && value == best})
+	}
+	return row
+}
+
+func formatMultiplier(v float64) string { return "x" + strconv.FormatFloat(v, 'f', -1, 64) }
+
+func formatInt(v float64) string { return strconv.Itoa(int(v)) }
+
+// comparePokemon looks up a Pokemon to compare: one that has been caught,
+// or any Pokemon when anyPokemon is true.
+func (c *Config) comparePokemon(input string, anyPokemon bool) (pokeapi.PokemonDetails, error) {
+	name, err := pokeapi.ResolvePokemon(input)
+	if err != nil {
+		return pokeapi.PokemonDetails{}, notFound(err, "pokemon", input, pokeapi.PokemonNames)
+	}
+	if pokemon, err := c.Pokedex.InspectPokemon(name); err == nil {
+		return pokemon, nil
+	}
+	if !anyPokemon {
+		return pokeapi.PokemonDetails{}, fmt.Errorf("you haven't caught %s. Use --any to compare Pokemon you haven't caught", c.pokemonName(name))
+	}
+	pokemon, err := pokeapi.GetPokemon(name)
+	if err != nil {
+		return pokeapi.PokemonDetails{}, notFound(err, "pokemon", name, pokeapi.PokemonNames)
+	}
+	return pokemon, nil
+}
+
+func commandCompare(c *Config, args Args) error {
+	if len(args.Positional) < 2 {
+		return errors.New("compare needs at least two Pokemon\nusage: compare <pokemon> <pokemon> [pokemon...]")
+	}
+	var all []pokeapi.PokemonDetails
+	for _, input := range args.Positional {
+		pokemon, err := c.comparePokemon(input, args.Bool("any"))
+		if err != nil {
+			return err
+		}
+		all = append(all, pokemon)
+	}
+
+	header := compareRow{}
+	var types, abilities []string
+	var matchups []map[string]float64
+	var attackers []string
+	for _, pokemon := range all {
+		header.cells = append(header.cells, compareCell{text: c.pokemonName(pokemon.Name)})
+
+		typeNames := c.typeNames(pokemon)
+		types = append(types, strings.Join(c.typeLabels(typeNames), "/"))
+
+		var names []string
+		for _, ability := range pokemon.Abilities {
+			name := ability.Ability.Name
+			if ability.IsHidden {
+				name += " (hidden)"
+			}
+			names = append(names, name)
+		}
+		abilities = append(abilities, strings.Join(names, ", "))
+
+		m, err := pokeapi.DefensiveMatchups(typeNames)
+		if err != nil {
+			return err
+		}
+		matchups = append(matchups, m)
+		for attacker := range m {
+			if !slices.Contains(attackers, attacker) {
+				attackers = append(attackers, attacker)
+			}
+		}
+	}
+
+	rows := []compareRow{header, textRow("Types", types)}
+	for _, stat := range compareStats {
+		values := make([]float64, len(all))
+		for i, pokemon := range all {
+			base, _ := pokedex.BaseStat(pokemon, stat.name)
+			values[i] = float64(base)
+		}
+		rows = append(rows, numberRow(stat.label, values, false, formatInt))
+	}
+	totals, heights, weights := make([]float64, len(all)), make([]float64, len(all)), make([]float64, len(all))
+	for i, pokemon := range all {
+		totals[i] = float64(pokedex.BaseStatTotal(pokemon))
+		heights[i] = float64(pokemon.Height) / 10
+		weights[i] = float64(pokemon.Weight) / 10
+	}
+	rows = append(rows,
+		numberRow("Total", totals, false, formatInt),
+		numberRow("Height", heights, false, func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) + " m" }),
+		numberRow("Weight", weights, false, func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) + " kg" }),
+		textRow("Abilities", abilities),
+	)
+
+	// One row per attacking type that isn't neutral against all of them;
+	// taking less damage is better.
+	if len(attackers) > 0 {
+		rows = append(rows, compareRow{label: "Damage taken"})
+	}
+	slices.Sort(attackers)
+	for _, attacker := range attackers {
+		values := make([]float64, len(all))
+		for i, m := range matchups {
+			values[i] = 1
+			if multiplier, ok := m[attacker]; ok {
+				values[i] = multiplier
+			}
+		}
+		rows = append(rows, numberRow("  "+c.typeName(attacker), values, true, formatMultiplier))
+	}
+	c.printCompare(rows)
+	return nil
+}
+
+// compareHighlight marks the best value in a row on color terminals;
+// elsewhere it gets a trailing "*".
+var compareHighlight = termout.Bold.With(termout.Green)
+
+// printCompare writes rows in aligned columns.
+func (c *Config) printCompare(rows []compareRow) {
+	labelWidth := 0
+	widths := make([]int, len(rows[0].cells))
+	for _, row := range rows {
+		labelWidth = max(labelWidth, termout.Width(row.label))
+		for i, cell := range row.cells {
+			width := termout.Width(cell.text)
+			if cell.best && !c.Out.Color {
+				width++
+			}
+			widths[i] = max(widths[i], width)
+		}
+	}
+	for _, row := range rows {
+		var b strings.Builder
+		b.WriteString(termout.Pad(row.label, labelWidth))
+		for i, cell := range row.cells {
+			b.WriteString("  ")
+			text := cell.text
+			if cell.best {
+				if c.Out.Color {
+					text = c.Out.Paint(text, compareHighlight)
+				} else {
+					text += "*"
+				}
+			}
+			padded := termout.Pad(text, widths[i])
+			if i == len(row.cells)-1 {
+				padded = strings.TrimRight(padded, " ")
+			}
+			b.WriteString(padded)
+		}
+		fmt.Fprintln(c.Out, strings.TrimRight(b.String(), " "))
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+// encounterRow is one way to meet a Pokemon: a method and set of conditions,
+// with the chances of all its slots added up.
+type encounterRow struct {
+	// Name is the Pokemon met, or the area it's met in when listing where
+	// a Pokemon lives.
+	Name       string
+	Method     string
+	Chance     int
+	MinLevel   int
+	MaxLevel   int
+	Conditions []string
+	// Versions lists the games this row applies to.
+	Versions []string
+}
+
+func (r encounterRow) levels() string {
+	if r.MinLevel == r.MaxLevel {
+		return fmt.Sprint(r.MinLevel)
+	}
+	return fmt.Sprintf("%d-%d", r.MinLevel, r.MaxLevel)
+}
+
+func (r encounterRow) conditions() string {
+	if len(r.Conditions) == 0 {
+		return "-"
+	}
+	return strings.Join(r.Conditions, ", ")
+}
+
+// summarizeEncounters folds a Pokemon's encounter slots into rows. Slots
+// with the same method and conditions in a version are added together, and
+// versions with identical rows share one. Empty version or method match
+// everything.
+func summarizeEncounters(name string, details []pokeapi.VersionEncounterDetail, version, method string) []encounterRow {
+	var rows []encounterRow
+	for _, vd := range details {
+		if version != "" && vd.Version.Name != version {
+			continue
+		}
+		var perVersion []encounterRow
+		for _, ed := range vd.EncounterDetails {
+			if method != "" && ed.Method.Name != method {
+				continue
+			}
+			var conditions []string
+			for _, condition := range ed.ConditionValues {
+				conditions = append(conditions, condition.Name)
+			}
+			i := slices.IndexFunc(perVersion, func(r encounterRow) bool {
+				return r.Method == ed.Method.Name && slices.Equal(r.Conditions, conditions)
+			})
+			if i < 0 {
+				perVersion = append(perVersion, encounterRow{
+					Name:       name,
+					Method:     ed.Method.Name,
+					MinLevel:   ed.MinLevel,
+					MaxLevel:   ed.MaxLevel,
+					Conditions: conditions,
+				})
+				i = len(perVersion) - 1
+			}
+			row := &perVersion[i]
+			row.Chance += ed.Chance
+			row.MinLevel = min(row.MinLevel, ed.MinLevel)
+			row.MaxLevel = max(row.MaxLevel, ed.MaxLevel)
+		}
+
+		for _, row := range perVersion {
+			i := slices.IndexFunc(rows, func(r encounterRow) bool {
+				return r.Method == row.Method && r.Chance == row.Chance &&
+					r.MinLevel == row.MinLevel && r.MaxLevel == row.MaxLevel &&
+					slices.Equal(r.Conditions, row.Conditions)
+			})
+			if i < 0 {
+				rows = append(rows, row)
+				i = len(rows) - 1
+			}
+			rows[i].Versions = append(rows[i].Versions, vd.Version.Name)
+		}
+	}
+	return rows
+}
+
+// sortEncounters orders rows by method, then most likely first.
+func sortEncounters(rows []encounterRow) {
+	slices.SortStableFunc(rows, func(a, b encounterRow) int {
+		return cmp.Or(
+			strings.Compare(a.Method, b.Method),
+			cmp.Compare(b.Chance, a.Chance),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+// sortByChance orders rows most likely first, regardless of method.
+func sortByChance(rows []encounterRow) {
+	slices.SortStableFunc(rows, func(a, b encounterRow) int {
+		return cmp.Or(
+			cmp.Compare(b.Chance, a.Chance),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+// encounterTable chooses the columns printed for encounter rows. The
+// method column is left out when rows are already grouped by method, and
+// the versions column when a single version was asked for.
+type encounterTable struct {
+	NameHeader   string
+	ShowMethod   bool
+	ShowVersions bool
+	// Numbered adds a # column so rows can be referred to later.
+	Numbered bool
+}
+
+// print writes rows as aligned columns.
+func (t encounterTable) print(out io.Writer, rows []encounterRow) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	var header []string
+	if t.Numbered {
+		header = append(header, "#")
+	}
+	header = append(header, t.NameHeader)
+	if t.ShowMethod {
+		header = append(header, "METHOD")
+	}
+	header = append(header, "CHANCE", "LEVELS", "CONDITIONS")
+	if t.ShowVersions {
+		header = append(header, "VERSIONS")
+	}
+	fmt.Fprintf(w, "  %s\n", strings.Join(header, "\t"))
+	for i, row := range rows {
+		var cells []string
+		if t.Numbered {
+			cells = append(cells, fmt.Sprint(i+1))
+		}
+		cells = append(cells, row.Name)
+		if t.ShowMethod {
+			cells = append(cells, row.Method)
+		}
+		cells = append(cells, fmt.Sprintf("%d%%", row.Chance), row.levels(), row.conditions())
+		if t.ShowVersions {
+			cells = append(cells, strings.Join(row.Versions, ", "))
+		}
+		fmt.Fprintf(w, "  %s\n", strings.Join(cells, "\t"))
+	}
+	w.Flush()
+}
+
+// encounterVersions returns every version with encounters in the area.
+func encounterVersions(details pokeapi.LocationDetails) []string {
+	var versions []string
+	for _, encounter := range details.PokemonEncounters {
+		for _, vd := range encounter.VersionDetails {
+			if !slices.Contains(versions, vd.Version.Name) {
+				versions = append(versions, vd.Version.Name)
+			}
+		}
+	}
+	return versions
+}
+
+// encounterMethods returns every encounter method used in the area, in the
+// order the area lists its method rates.
+func encounterMethods(details pokeapi.LocationDetails) []string {
+	var methods []string
+	for _, rate := range details.EncounterMethodRates {
+		methods = append(methods, rate.EncounterMethod.Name)
+	}
+	for _, encounter := range details.PokemonEncounters {
+		for _, vd := range encounter.VersionDetails {
+			for _, ed := range vd.EncounterDetails {
+				if !slices.Contains(methods, ed.Method.Name) {
+					methods = append(methods, ed.Method.Name)
+				}
+			}
+		}
+	}
+	return methods
+}
+
+// methodRate describes how often a method triggers an encounter, e.g.
+// "rate 10" or "rate 10-25" when versions differ.
+func methodRate(details pokeapi.LocationDetails, method, version string) string {
+	low, high := -1, -1
+	for _, rate := range details.EncounterMethodRates {
+		if rate.EncounterMethod.Name != method {
+			continue
+		}
+		for _, vd := range rate.VersionDetails {
+			if version != "" && vd.Version.Name != version {
+				continue
+			}
+			if low < 0 || vd.Rate < low {
+				low = vd.Rate
+			}
+			high = max(high, vd.Rate)
+		}
+	}
+	switch {
+	case low < 0:
+		return ""
+	case low == high:
+		return fmt.Sprintf("rate %d", low)
+	default:
+		return fmt.Sprintf("rate %d-%d", low, high)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shamsup/pokedexcli/pokedex"
+)
+
+// exportFormat picks the export format from --format, or else from the
+// file's extension.
+func exportFormat(file string, args Args) (string, error) {
+	if args.Has("format") {
+		return args.Flag("format"), nil
+	}
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(file), "."))
+	if format == "markdown" {
+		format = "md"
+	}
+	if _, ok := pokedex.Exporters[format]; !ok {
+		return "", fmt.Errorf("can't tell the format of %s; use --format %s", file, strings.Join(pokedex.ExportFormats(), "|"))
+	}
+	return format, nil
+}
+
+func commandExport(c *Config, args Args) error {
+	file := args.Positional[0]
+	format, err := exportFormat(file, args)
+	if err != nil {
+		return err
+	}
+	if _, ok := pokedex.Exporters[format]; !ok {
+		return fmt.Errorf("unknown format %q.%s", format, didYouMean(format, pokedex.ExportFormats()))
+	}
+
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	count, err := c.Pokedex.Export(out, format, pokedex.Query{})
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave half an export behind.
+		return errors.Join(fmt.Errorf("exporting to %s: %w", file, err), os.Remove(file))
+	}
+	fmt.Fprintf(c.Out, "Exported %d Pokemon to %s\n", count, file)
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+	"github.com/shamsup/pokedexcli/pokedex/sqlitestore"
+)
+
+// mergeOutcomes describes what each strategy did with a conflict.
+var mergeOutcomes = map[pokedex.MergeStrategy]string{
+	pokedex.MergeKeep:      "kept yours",
+	pokedex.MergeOverwrite: "replaced with theirs",
+	pokedex.MergeUnion:     "combined",
+}
+
+func init() {
+	// The saved Pokedex, as in --db, imports like an export.
+	pokedex.Importers["db"] = pokedex.ImporterFunc(sqlitestore.Import)
+}
+
+// sqliteHeader starts every SQLite database file.
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// importFormat picks the import format from --format, the file's
+// extension, or else its contents: JSON exports start with "{" and saved
+// databases with the SQLite header.
+func importFormat(file string, data []byte, args Args) string {
+	if args.Has("format") {
+		return args.Flag("format")
+	}
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(file), "."))
+	if _, ok := pokedex.Importers[format]; ok {
+		return format
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return "json"
+	}
+	if bytes.HasPrefix(data, sqliteHeader) {
+		return "db"
+	}
+	return "csv"
+}
+
+func commandImport(c *Config, args Args) error {
+	strategy := pokedex.MergeKeep
+	if args.Has("strategy") {
+		var err error
+		if strategy, err = pokedex.ParseMergeStrategy(args.Flag("strategy")); err != nil {
+			return err
+		}
+	}
+
+	file := args.Positional[0]
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	format := importFormat(file, data, args)
+	importer, ok := pokedex.Importers[format]
+	if !ok {
+		return fmt.Errorf("can't import %q files.%s", format, didYouMean(format, pokedex.ImportFormats()))
+	}
+	records, err := importer.Import(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("importing %s: %w", file, err)
+	}
+	// Accept names the way catch does, e.g. "Mr. Mime" or a number.
+	for i, record := range records {
+		if strings.TrimSpace(record.Name) == "" {
+			continue
+		}
+		if name, err := pokeapi.ResolvePokemon(record.Name); err == nil {
+			records[i].Name = name
+		}
+	}
+
+	report, err := c.Pokedex.Import(records, strategy)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Imported %s:\n", file)
+	c.printImported("Added", report.Added, "")
+	c.printImported("Conflicts", report.Conflicts, " ("+mergeOutcomes[strategy]+")")
+	if len(report.Unchanged) > 0 {
+		fmt.Fprintf(c.Out, "  Already caught: %d\n", len(report.Unchanged))
+	}
+	if len(report.Invalid) > 0 {
+		names := make([]string, 0, len(report.Invalid))
+		for name := range report.Invalid {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		fmt.Fprintf(c.Out, "  Skipped %d:\n", len(names))
+		for _, name := range names {
+			reason := report.Invalid[name].Error()
+			if errors.Is(report.Invalid[name], pokeapi.ErrNotFound) {
+				reason = "no Pokemon by that name"
+			}
+			fmt.Fprintf(c.Out, "   - %s: %s\n", name, reason)
+		}
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(c.Out, "  The file has no Pokemon")
+	}
+	return nil
+}
+
+func (c *Config) printImported(label string, names []string, note string) {
+	if len(names) == 0 {
+		return
+	}
+	display := make([]string, len(names))
+	for i, name := range names {
+		display[i] = c.pokemonName(name)
+	}
+	fmt.Fprintf(c.Out, "  %s %d%s: %s\n", label, len(names), note, strings.Join(display, ", "))
+}
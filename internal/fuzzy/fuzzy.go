@@ -0,0 +1,80 @@
+// Package fuzzy finds the closest matches for a misspelled name.
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+)
+
+// Distance is the optimal string alignment distance between a and b: the
+// number of insertions, deletions, substitutions and adjacent swaps needed
+// to turn one into the other.
+func Distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// d[i][j] is the distance between s[:i] and t[:j].
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+// Suggest returns up to limit candidates close enough to input to be
+// likely typos, best match first. It returns nothing if input is itself a
+// candidate. A candidate that starts with input counts
+// as a close match so partial names like "canalave" still find
+// "canalave-city-area".
+func Suggest(input string, candidates []string, limit int) []string {
+	input = strings.ToLower(input)
+	if slices.Contains(candidates, input) {
+		return nil
+	}
+	// Allow roughly one mistake per three characters.
+	threshold := max(1, len([]rune(input))/3)
+
+	type match struct {
+		name  string
+		score int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		score := Distance(input, candidate)
+		if len(input) >= 3 && strings.HasPrefix(candidate, input) {
+			score = min(score, 1)
+		}
+		if score <= threshold {
+			matches = append(matches, match{candidate, score})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.score != b.score {
+			return a.score - b.score
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	var names []string
+	for _, m := range matches {
+		if len(names) == limit {
+			break
+		}
+		names = append(names, m.name)
+	}
+	return names
+}
@@ -0,0 +1,52 @@
+package fuzzy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"pikachu", "pikachu", 0},
+		{"pikchu", "pikachu", 1},
+		{"pikahcu", "pikachu", 1},
+		{"mpa", "map", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"ピカチュウ", "ピカチュ", 1},
+	}
+	for _, c := range cases {
+		if actual := Distance(c.a, c.b); actual != c.expected {
+			t.Errorf("Distance(%q, %q): expected %d, got %d", c.a, c.b, c.expected, actual)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	names := []string{"pikachu", "raichu", "pichu", "charmander", "charmeleon", "canalave-city-area", "map", "mapb"}
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{"pikchu", []string{"pichu", "pikachu"}},
+		{"Charmandr", []string{"charmander"}},
+		{"mpa", []string{"map"}},
+		{"mapp", []string{"map", "mapb"}},
+		{"canalave", []string{"canalave-city-area"}},
+		{"zzzzzz", nil},
+		{"pikachu", nil},
+	}
+	for _, c := range cases {
+		if actual := Suggest(c.input, names, 3); !slices.Equal(actual, c.expected) {
+			t.Errorf("Suggest(%q): expected %v, got %v", c.input, c.expected, actual)
+		}
+	}
+
+	if actual := Suggest("pikchu", names, 1); !slices.Equal(actual, []string{"pichu"}) {
+		t.Errorf("expected the limit to keep the best match, got %v", actual)
+	}
+}
@@ -0,0 +1,118 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultHistorySize = 1000
+
+// History remembers entered lines, optionally persisting them to a file so
+// they survive between sessions.
+type History struct {
+	path    string
+	limit   int
+	entries []string
+	// saved counts the lines in the file at path, so it can be rewritten
+	// once it grows past limit.
+	saved int
+}
+
+// NewHistory creates an empty history that appends to the file at path,
+// keeping only the last limit lines there too. An empty path keeps history
+// in memory only. A limit of zero uses the default.
+func NewHistory(path string, limit int) *History {
+	if limit <= 0 {
+		limit = defaultHistorySize
+	}
+	return &History{path: path, limit: limit}
+}
+
+// Load reads previously saved lines from the history file. A missing file
+// is not an error.
+func (h *History) Load() error {
+	if h.path == "" {
+		return nil
+	}
+	f, err := os.Open(h.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		h.push(scanner.Text())
+		h.saved++
+	}
+	return scanner.Err()
+}
+
+// Add records a line. Blank lines and repeats of the previous line are
+// skipped.
+func (h *History) Add(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == line {
+		return
+	}
+	h.push(line)
+	if h.path == "" {
+		return
+	}
+	// History is a convenience, so failing to save it isn't worth
+	// interrupting the user over.
+	if h.saved >= h.limit {
+		h.rewrite()
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line + "\n"); err == nil {
+		h.saved++
+	}
+}
+
+// rewrite replaces the history file with the lines in memory, dropping the
+// ones past the limit. The new file is moved into place so a failure leaves
+// the old one intact.
+func (h *History) rewrite() {
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(strings.Join(h.entries, "\n") + "\n")
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+	if os.Rename(tmp.Name(), h.path) == nil {
+		h.saved = len(h.entries)
+	}
+}
+
+func (h *History) push(line string) {
+	h.entries = append(h.entries, line)
+	if over := len(h.entries) - h.limit; over > 0 {
+		h.entries = h.entries[over:]
+	}
+}
+
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// At returns the i'th oldest line.
+func (h *History) At(i int) string {
+	return h.entries[i]
+}
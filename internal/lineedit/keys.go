@@ -0,0 +1,102 @@
+package lineedit
+
+import (
+	"bufio"
+	"strings"
+	"unicode"
+)
+
+// Control keys as a terminal in raw mode sends them.
+const (
+	KeyCtrlA     = 1
+	KeyCtrlB     = 2
+	KeyCtrlC     = 3
+	KeyCtrlD     = 4
+	KeyCtrlE     = 5
+	KeyCtrlF     = 6
+	KeyCtrlG     = 7
+	KeyBackspace = 8
+	KeyTab       = 9
+	KeyLF        = 10
+	KeyCtrlK     = 11
+	KeyCtrlL     = 12
+	KeyCR        = 13
+	KeyCtrlN     = 14
+	KeyCtrlP     = 16
+	KeyCtrlR     = 18
+	KeyCtrlU     = 21
+	KeyCtrlW     = 23
+	KeyEscape    = 27
+	KeyDelete    = 127
+)
+
+// Synthetic keys for escape sequences, outside the Unicode range.
+const (
+	KeyUp rune = unicode.MaxRune + 1 + iota
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	// KeyBackTab is Shift-Tab.
+	KeyBackTab
+	KeyDeleteForward
+	KeyUnknown
+)
+
+// ReadKey reads a key from a terminal in raw mode, decoding the escape
+// sequences it sends for arrows and other special keys. An escape with
+// nothing after it is the Escape key itself.
+func ReadKey(in *bufio.Reader) (rune, error) {
+	r, _, err := in.ReadRune()
+	if err != nil || r != KeyEscape {
+		return r, err
+	}
+	if in.Buffered() == 0 {
+		return KeyEscape, nil
+	}
+	next, _, err := in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if next != '[' && next != 'O' {
+		return KeyUnknown, nil
+	}
+	// Read up to the sequence's final byte, e.g. "A" or "5~".
+	var seq strings.Builder
+	for {
+		b, _, err := in.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		seq.WriteRune(b)
+		if b >= 0x40 && b <= 0x7e {
+			break
+		}
+	}
+	switch seq.String() {
+	case "A":
+		return KeyUp, nil
+	case "B":
+		return KeyDown, nil
+	case "C":
+		return KeyRight, nil
+	case "D":
+		return KeyLeft, nil
+	case "H", "1~", "7~":
+		return KeyHome, nil
+	case "F", "4~", "8~":
+		return KeyEnd, nil
+	case "3~":
+		return KeyDeleteForward, nil
+	case "5~":
+		return KeyPageUp, nil
+	case "6~":
+		return KeyPageDown, nil
+	case "Z":
+		return KeyBackTab, nil
+	}
+	return KeyUnknown, nil
+}
@@ -0,0 +1,337 @@
+// Package lineedit reads lines from a terminal with cursor movement,
+// history, reverse search and tab completion.
+//
+// The Editor expects a terminal that is already in raw mode; see MakeRaw.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/shamsup/pokedexcli/internal/termout"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+// Completer returns the candidates for the word being typed at the end of
+// line. Candidates are whole words; the Editor works out what to insert.
+type Completer func(line string) []string
+
+type Editor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	history  *History
+	complete Completer
+	// width returns the terminal's width in cells, or 0 if it's unknown and
+	// lines are assumed not to wrap.
+	width func() int
+
+	// State for the line being edited.
+	prompt  string
+	line    []rune
+	pos     int
+	histPos int
+	draft   []rune
+	lastTab bool
+	// row is the screen row the cursor was left on, counted from the row
+	// the prompt starts on, as a long line wraps onto several.
+	row int
+}
+
+// New creates an Editor reading keys from in and drawing to out. history
+// and complete may be nil.
+func New(in io.Reader, out io.Writer, history *History, complete Completer) *Editor {
+	if history == nil {
+		history = NewHistory("", 0)
+	}
+	return &Editor{
+		in:       bufio.NewReader(in),
+		out:      out,
+		history:  history,
+		complete: complete,
+		width:    terminalWidth(out),
+	}
+}
+
+// ReadLine shows prompt and returns the line the user enters. It returns
+// io.EOF on Ctrl-D at an empty line and ErrInterrupted on Ctrl-C.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	e.prompt = prompt
+	e.line = e.line[:0]
+	e.pos = 0
+	e.histPos = e.history.Len()
+	e.draft = nil
+	e.lastTab = false
+	e.row = 0
+	e.refresh()
+
+	for {
+		key, err := e.readKey()
+		if err != nil {
+			return "", err
+		}
+		tab := false
+		switch key {
+		case KeyCR, KeyLF:
+			line := string(e.line)
+			e.finish()
+			e.history.Add(line)
+			return line, nil
+		case KeyCtrlC:
+			e.write("^C")
+			e.finish()
+			return "", ErrInterrupted
+		case KeyCtrlD:
+			if len(e.line) == 0 {
+				e.finish()
+				return "", io.EOF
+			}
+			e.deleteForward()
+		case KeyCtrlA, KeyHome:
+			e.pos = 0
+		case KeyCtrlE, KeyEnd:
+			e.pos = len(e.line)
+		case KeyCtrlB, KeyLeft:
+			e.pos = max(e.pos-1, 0)
+		case KeyCtrlF, KeyRight:
+			e.pos = min(e.pos+1, len(e.line))
+		case KeyBackspace, KeyDelete:
+			if e.pos > 0 {
+				e.line = slices.Delete(e.line, e.pos-1, e.pos)
+				e.pos--
+			}
+		case KeyDeleteForward:
+			e.deleteForward()
+		case KeyCtrlK:
+			e.line = e.line[:e.pos]
+		case KeyCtrlU:
+			e.line = slices.Delete(e.line, 0, e.pos)
+			e.pos = 0
+		case KeyCtrlW:
+			start := wordStart(e.line, e.pos)
+			e.line = slices.Delete(e.line, start, e.pos)
+			e.pos = start
+		case KeyCtrlL:
+			e.write("\x1b[H\x1b[2J")
+			e.row = 0
+		case KeyCtrlP, KeyUp:
+			e.historyMove(-1)
+		case KeyCtrlN, KeyDown:
+			e.historyMove(1)
+		case KeyTab:
+			e.tabComplete()
+			tab = true
+		case KeyCtrlR:
+			line, accepted, err := e.reverseSearch()
+			if err != nil {
+				return "", err
+			}
+			e.line = []rune(line)
+			e.pos = len(e.line)
+			if accepted {
+				e.finish()
+				e.history.Add(line)
+				return line, nil
+			}
+		default:
+			if unicode.IsPrint(key) {
+				e.line = slices.Insert(e.line, e.pos, key)
+				e.pos++
+			}
+		}
+		e.lastTab = tab
+		e.refresh()
+	}
+}
+
+func (e *Editor) deleteForward() {
+	if e.pos < len(e.line) {
+		e.line = slices.Delete(e.line, e.pos, e.pos+1)
+	}
+}
+
+func (e *Editor) historyMove(delta int) {
+	next := e.histPos + delta
+	if next < 0 || next > e.history.Len() {
+		return
+	}
+	if e.histPos == e.history.Len() {
+		e.draft = slices.Clone(e.line)
+	}
+	e.histPos = next
+	if next == e.history.Len() {
+		e.line = slices.Clone(e.draft)
+	} else {
+		e.line = []rune(e.history.At(next))
+	}
+	e.pos = len(e.line)
+}
+
+// tabComplete completes the word before the cursor. A single candidate is
+// inserted in full; several candidates are extended to their common prefix,
+// and listed if the user presses Tab again.
+func (e *Editor) tabComplete() {
+	if e.complete == nil {
+		return
+	}
+	before := string(e.line[:e.pos])
+	candidates := e.complete(before)
+	if len(candidates) == 0 {
+		return
+	}
+	start := e.pos
+	for start > 0 && e.line[start-1] != ' ' {
+		start--
+	}
+	word := string(e.line[start:e.pos])
+
+	insert := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		insert += " "
+	}
+	if len(insert) > len(word) && strings.HasPrefix(insert, word) {
+		e.replaceWord(start, insert)
+		return
+	}
+	if len(candidates) > 1 && e.lastTab {
+		e.draw(e.prompt+string(e.line), termout.Width(e.prompt+string(e.line)))
+		e.write("\r\n" + strings.Join(candidates, "  ") + "\r\n")
+		e.row = 0
+	}
+}
+
+func (e *Editor) replaceWord(start int, word string) {
+	replacement := []rune(word)
+	e.line = slices.Replace(e.line, start, e.pos, replacement...)
+	e.pos = start + len(replacement)
+}
+
+// reverseSearch runs an incremental search backwards through history. It
+// returns the matched line and whether the user pressed Enter to run it.
+func (e *Editor) reverseSearch() (string, bool, error) {
+	original := string(e.line)
+	var query []rune
+	match, index := "", e.history.Len()
+	search := func(from int) {
+		for i := from - 1; i >= 0; i-- {
+			if strings.Contains(e.history.At(i), string(query)) {
+				match, index = e.history.At(i), i
+				return
+			}
+		}
+	}
+	for {
+		status := fmt.Sprintf("(reverse-i-search)`%s': %s", string(query), match)
+		e.draw(status, termout.Width(status))
+		key, err := e.readKey()
+		if err != nil {
+			return "", false, err
+		}
+		switch key {
+		case KeyCR, KeyLF:
+			return match, true, nil
+		case KeyCtrlC, KeyCtrlG, KeyEscape:
+			return original, false, nil
+		case KeyCtrlR:
+			search(index)
+		case KeyBackspace, KeyDelete:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				match, index = "", e.history.Len()
+				search(index)
+			}
+		default:
+			if unicode.IsPrint(key) {
+				query = append(query, key)
+				if !strings.Contains(match, string(query)) {
+					search(min(index+1, e.history.Len()))
+				}
+				continue
+			}
+			// Any other key leaves the match on the line for editing.
+			return match, false, nil
+		}
+	}
+}
+
+func (e *Editor) readKey() (rune, error) {
+	return ReadKey(e.in)
+}
+
+func (e *Editor) refresh() {
+	e.draw(e.prompt+string(e.line), termout.Width(e.prompt+string(e.line[:e.pos])))
+}
+
+// draw replaces what the last draw showed with text, which may wrap onto
+// several rows, and moves the cursor to the cell at offset cursor in it.
+func (e *Editor) draw(text string, cursor int) {
+	var b strings.Builder
+	if e.row > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", e.row)
+	}
+	b.WriteString("\r")
+	b.WriteString(text)
+	b.WriteString("\x1b[J")
+	end := termout.Width(text)
+	width := e.width()
+	endRow, row, col := 0, 0, cursor
+	if width > 0 {
+		// A terminal holds the cursor on the last column after filling a
+		// row, so start the next one to know where it is.
+		if end > 0 && end%width == 0 {
+			b.WriteString("\r\n")
+		}
+		endRow, row, col = end/width, cursor/width, cursor%width
+	}
+	if up := endRow - row; up > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", up)
+	}
+	b.WriteString("\r")
+	if col > 0 {
+		fmt.Fprintf(&b, "\x1b[%dC", col)
+	}
+	e.row = row
+	e.write(b.String())
+}
+
+func (e *Editor) finish() {
+	e.pos = len(e.line)
+	e.refresh()
+	// draw has already started a new row if the line filled the last one.
+	end, width := termout.Width(e.prompt+string(e.line)), e.width()
+	if width == 0 || end == 0 || end%width != 0 {
+		e.write("\r\n")
+	}
+	e.row = 0
+}
+
+func (e *Editor) write(s string) {
+	io.WriteString(e.out, s)
+}
+
+func wordStart(line []rune, pos int) int {
+	start := pos
+	for start > 0 && line[start-1] == ' ' {
+		start--
+	}
+	for start > 0 && line[start-1] != ' ' {
+		start--
+	}
+	return start
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,196 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func readLines(t *testing.T, e *Editor, n int) []string {
+	t.Helper()
+	var lines []string
+	for i := 0; i < n; i++ {
+		line, err := e.ReadLine("> ")
+		if err != nil {
+			t.Fatalf("line %d: unexpected error %v", i, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestEditing(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "catch pikachu\r", "catch pikachu"},
+		{"backspace", "catxh\x7f\x7fch\r", "catch"},
+		{"arrows insert mid-line", "cach\x1b[D\x1b[Dt\r", "catch"},
+		{"home and end", "atc\x1b[Hc\x1b[Fh\r", "catch"},
+		{"ctrl-a ctrl-e", "atc\x01c\x05h\r", "catch"},
+		{"delete forward", "xcatch\x01\x1b[3~\r", "catch"},
+		{"kill to end", "catch pikachu\x01\x06\x06\x06\x06\x06\x0b\r", "catch"},
+		{"kill to start", "oops catch\x1b[D\x1b[D\x1b[D\x1b[D\x1b[D\x15\r", "catch"},
+		{"delete word", "catch pikachu\x17bulbasaur\r", "catch bulbasaur"},
+		{"unicode", "catch ピカチュウ\r", "catch ピカチュウ"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := New(strings.NewReader(c.input), io.Discard, nil, nil)
+			if line := readLines(t, e, 1)[0]; line != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, line)
+			}
+		})
+	}
+}
+
+func TestControlKeys(t *testing.T) {
+	e := New(strings.NewReader("abc\x03\x04"), io.Discard, nil, nil)
+	if _, err := e.ReadLine("> "); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("expected ErrInterrupted, got %v", err)
+	}
+	if _, err := e.ReadLine("> "); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	keys := bufio.NewReader(strings.NewReader("j\x1b[A\x1b[6~\x1b[Z\x1bOH\x1b[3~\x1b[9~\x1b"))
+	want := []rune{'j', KeyUp, KeyPageDown, KeyBackTab, KeyHome, KeyDeleteForward, KeyUnknown, KeyEscape}
+	for _, expected := range want {
+		got, err := ReadKey(keys)
+		if err != nil || got != expected {
+			t.Errorf("expected key %d, got %d, %v", expected, got, err)
+		}
+	}
+}
+
+func TestHistoryNavigation(t *testing.T) {
+	input := "map\rexplore canalave-city-area\r" +
+		"\x1b[A\x1b[A\r" + // up twice: map
+		"draft\x1b[A\x1b[B\r" // up then down restores the draft
+	e := New(strings.NewReader(input), io.Discard, nil, nil)
+	lines := readLines(t, e, 4)
+	expected := []string{"map", "explore canalave-city-area", "map", "draft"}
+	if !slices.Equal(lines, expected) {
+		t.Errorf("expected %q, got %q", expected, lines)
+	}
+}
+
+func TestReverseSearch(t *testing.T) {
+	input := "catch pikachu\rmap\rexplore canalave-city-area\r" +
+		"\x12pik\r" + // search and run
+		"\x12a\x12\x12\x1b[C!\r" // cycle to older matches, then edit the result
+	e := New(strings.NewReader(input), io.Discard, nil, nil)
+	lines := readLines(t, e, 5)
+	if lines[3] != "catch pikachu" {
+		t.Errorf("expected reverse search to find catch pikachu, got %q", lines[3])
+	}
+	// Matches for "a", newest first: catch pikachu, explore ..., map.
+	if lines[4] != "map!" {
+		t.Errorf("expected to edit the third match, got %q", lines[4])
+	}
+}
+
+func TestTabCompletion(t *testing.T) {
+	complete := func(line string) []string {
+		words := strings.Fields(line)
+		if len(words) == 0 || (len(words) == 1 && !strings.HasSuffix(line, " ")) {
+			var names []string
+			for _, name := range []string{"catch", "compare", "explore"} {
+				if len(words) == 0 || strings.HasPrefix(name, words[0]) {
+					names = append(names, name)
+				}
+			}
+			return names
+		}
+		return []string{"pikachu"}
+	}
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"ex\t\r", "explore "},
+		{"c\t\r", "c"},
+		{"ca\tpi\t\r", "catch pikachu "},
+		{"catch \t\r", "catch pikachu "},
+	}
+	for _, c := range cases {
+		e := New(strings.NewReader(c.input), io.Discard, nil, complete)
+		if line := readLines(t, e, 1)[0]; line != c.expected {
+			t.Errorf("%q: expected %q, got %q", c.input, c.expected, line)
+		}
+	}
+}
+
+func TestHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	h := NewHistory(path, 2)
+	for _, line := range []string{"map", "map", " ", "mapb", "pokedex"} {
+		h.Add(line)
+	}
+	if h.Len() != 2 || h.At(0) != "mapb" || h.At(1) != "pokedex" {
+		t.Errorf("expected the last two unique lines, got %v", h.entries)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "mapb\npokedex\n" {
+		t.Errorf("expected the file to keep only the last two lines, got %q", data)
+	}
+
+	loaded := NewHistory(path, 3)
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Len() != 2 || loaded.At(1) != "pokedex" {
+		t.Errorf("expected to load saved history, got %v", loaded.entries)
+	}
+	loaded.Add("map")
+	loaded.Add("explore canalave-city-area")
+	if data, _ := os.ReadFile(path); string(data) != "pokedex\nmap\nexplore canalave-city-area\n" {
+		t.Errorf("expected the file to be rewritten past the limit, got %q", data)
+	}
+
+	missing := NewHistory(filepath.Join(t.TempDir(), "none"), 0)
+	if err := missing.Load(); err != nil {
+		t.Errorf("expected a missing history file to be ignored, got %v", err)
+	}
+}
+
+func TestRefreshWraps(t *testing.T) {
+	var out strings.Builder
+	e := New(strings.NewReader(""), &out, nil, nil)
+	e.width = func() int { return 10 }
+	e.prompt = "> "
+	steps := []struct {
+		line     string
+		pos      int
+		expected string
+		row      int
+	}{
+		// "ピ" is two cells wide, so the cursor after it starts row 1.
+		{"catch ピカチュウ", 7, "\r> catch ピカチュウ\x1b[J\r", 1},
+		{"catch ピカチュウ", 11, "\x1b[1A\r> catch ピカチュウ\x1b[J\r\x1b[8C", 1},
+		// Filling the first row exactly moves on to the second.
+		{"catch pi", 8, "\x1b[1A\r> catch pi\x1b[J\r\n\r", 1},
+		{"map", 0, "\x1b[1A\r> map\x1b[J\r\x1b[2C", 0},
+	}
+	for _, step := range steps {
+		out.Reset()
+		e.line, e.pos = []rune(step.line), step.pos
+		e.refresh()
+		if out.String() != step.expected || e.row != step.row {
+			t.Errorf("%q at %d: expected %q on row %d, got %q on row %d", step.line, step.pos, step.expected, step.row, out.String(), e.row)
+		}
+	}
+}
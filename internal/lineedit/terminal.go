@@ -0,0 +1,41 @@
+package lineedit
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// IsTerminal reports whether f is connected to a terminal.
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// MakeRaw puts the terminal connected to f into raw mode and returns a
+// function that restores its previous state.
+func MakeRaw(f *os.File) (func(), error) {
+	state, err := term.MakeRaw(int(f.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		term.Restore(int(f.Fd()), state)
+	}, nil
+}
+
+// terminalWidth returns a function that reports the width of the terminal
+// w writes to, which may change between lines, or 0 if w isn't one.
+func terminalWidth(w io.Writer) func() int {
+	f, ok := w.(*os.File)
+	if !ok || !IsTerminal(f) {
+		return func() int { return 0 }
+	}
+	return func() int {
+		width, _, err := term.GetSize(int(f.Fd()))
+		if err != nil {
+			return 0
+		}
+		return width
+	}
+}
@@ -0,0 +1,39 @@
+package pokeapitest
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestFixturePath(t *testing.T) {
+	cases := []struct {
+		url      string
+		expected string
+	}{
+		{"http://x/api/v2/location-area/", "location-area/index.json"},
+		{"http://x/api/v2/location-area/?offset=20&limit=20", "location-area/index_limit-20_offset-20.json"},
+		{"http://x/api/v2/pokemon/pikachu", "pokemon/pikachu.json"},
+		{"http://x/api/v2/pokemon/25/encounters", "pokemon/25/encounters.json"},
+	}
+	for _, c := range cases {
+		u, err := url.Parse(c.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := FixturePath(u)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.url, err)
+			continue
+		}
+		if actual != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.url, c.expected, actual)
+		}
+	}
+
+	for _, bad := range []string{"http://x/other/path", "http://x/api/v2/", "http://x/api/v2/pokemon/../../etc"} {
+		u, _ := url.Parse(bad)
+		if _, err := FixturePath(u); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Blob is a downloaded file and the HTTP metadata needed to revalidate it.
+type Blob struct {
+	Data         []byte    `json:"-"`
+	URL          string    `json:"url"`
+	ContentType  string    `json:"content_type,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// DiskCache keeps blobs in a directory so they outlive the process. Each
+// blob is stored as two files named after a hash of its URL: the data and
+// a JSON file with its metadata.
+type DiskCache struct {
+	dir string
+}
+
+func NewDiskCache(dir string) DiskCache {
+	return DiskCache{dir: dir}
+}
+
+func (c DiskCache) paths(url string) (data, meta string) {
+	sum := sha256.Sum256([]byte(url))
+	name := hex.EncodeToString(sum[:])
+	// Spread files over subdirectories so none grows too large.
+	base := filepath.Join(c.dir, name[:2], name)
+	return base + ".bin", base + ".json"
+}
+
+// Get returns the blob stored for url. A missing or damaged entry is
+// reported as not found.
+func (c DiskCache) Get(url string) (Blob, bool) {
+	dataPath, metaPath := c.paths(url)
+	meta, err := os.ReadFile(metaPath)
+	if err != nil {
+		return Blob{}, false
+	}
+	var blob Blob
+	if err := json.Unmarshal(meta, &blob); err != nil || blob.URL != url {
+		return Blob{}, false
+	}
+	blob.Data, err = os.ReadFile(dataPath)
+	if err != nil {
+		return Blob{}, false
+	}
+	return blob, true
+}
+
+// Add stores a blob under its URL, replacing any earlier copy. Files are
+// written to a temporary name first so a crash never leaves half a blob.
+func (c DiskCache) Add(blob Blob) error {
+	dataPath, metaPath := c.paths(blob.URL)
+	if err := os.MkdirAll(filepath.Dir(dataPath), 0o755); err != nil {
+		return err
+	}
+	meta, err := json.Marshal(blob)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(dataPath, blob.Data); err != nil {
+		return err
+	}
+	return writeFileAtomic(metaPath, meta)
+}
+
+// Touch records that the blob for url was revalidated at t.
+func (c DiskCache) Touch(url string, t time.Time) error {
+	blob, ok := c.Get(url)
+	if !ok {
+		return fs.ErrNotExist
+	}
+	blob.FetchedAt = t
+	_, metaPath := c.paths(url)
+	meta, err := json.Marshal(blob)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(metaPath, meta)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+	return nil
+}
@@ -59,3 +59,38 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 }
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewDiskCache(dir)
+	blob := Blob{
+		Data:        []byte("\x89PNG"),
+		URL:         "https://example.com/25.png",
+		ContentType: "image/png",
+		ETag:        `"abc"`,
+		FetchedAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := cache.Add(blob); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new cache over the same directory sees what the first one saved.
+	got, ok := NewDiskCache(dir).Get(blob.URL)
+	if !ok {
+		t.Fatal("expected to find the blob")
+	}
+	if string(got.Data) != string(blob.Data) || got.ContentType != blob.ContentType || got.ETag != blob.ETag || !got.FetchedAt.Equal(blob.FetchedAt) {
+		t.Errorf("expected %+v, got %+v", blob, got)
+	}
+	if _, ok := cache.Get("https://example.com/26.png"); ok {
+		t.Errorf("expected no blob for another url")
+	}
+
+	later := blob.FetchedAt.Add(time.Hour)
+	if err := cache.Touch(blob.URL, later); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := cache.Get(blob.URL); !got.FetchedAt.Equal(later) || string(got.Data) != string(blob.Data) {
+		t.Errorf("expected touch to only update the fetch time, got %+v", got)
+	}
+}
@@ -0,0 +1,245 @@
+// Package server exposes a Pokedex over a local HTTP JSON API.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+)
+
+const defaultPageSize = 20
+
+// API is the subset of the PokeAPI the server needs beyond the Pokedex.
+type API interface {
+	ResolvePokemon(input string) (string, error)
+	GetLocationsPage(offset, limit int) (pokeapi.PaginatedResponse[pokeapi.ListEntry], error)
+	GetLocationDetails(name string) (pokeapi.LocationDetails, error)
+}
+
+type DefaultAPI struct{}
+
+func (DefaultAPI) ResolvePokemon(input string) (string, error) {
+	return pokeapi.ResolvePokemon(input)
+}
+
+func (DefaultAPI) GetLocationsPage(offset, limit int) (pokeapi.PaginatedResponse[pokeapi.ListEntry], error) {
+	return pokeapi.GetLocationsPage(offset, limit)
+}
+
+func (DefaultAPI) GetLocationDetails(name string) (pokeapi.LocationDetails, error) {
+	return pokeapi.GetLocationDetails(name)
+}
+
+// Server routes requests to the Pokedex and the PokeAPI:
+//
+//	GET  /pokedex                 list caught Pokemon (sort, type, min-stat, limit, offset)
+//	GET  /pokedex/{name}          inspect a caught Pokemon
+//	POST /pokedex/{name}/catch    throw a Pokeball
+//	GET  /locations               one page of location areas (offset, limit)
+//	GET  /locations/{name}        explore a location area
+//
+// Pokemon may be named by number or localized name as well as API name.
+type Server struct {
+	dex *pokedex.Pokedex
+	api API
+	mux *http.ServeMux
+}
+
+func New(dex *pokedex.Pokedex, api API) *Server {
+	if api == nil {
+		api = DefaultAPI{}
+	}
+	s := &Server{dex: dex, api: api, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /pokedex", s.handleList)
+	s.mux.HandleFunc("GET /pokedex/{name}", s.handleInspect)
+	s.mux.HandleFunc("POST /pokedex/{name}/catch", s.handleCatch)
+	s.mux.HandleFunc("GET /locations", s.handleLocations)
+	s.mux.HandleFunc("GET /locations/{name}", s.handleExplore)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// PokemonSummary is how a caught Pokemon appears in lists.
+type PokemonSummary struct {
+	ID       int       `json:"id"`
+	Name     string    `json:"name"`
+	Types    []string  `json:"types"`
+	BST      int       `json:"bst"`
+	CaughtAt time.Time `json:"caught_at"`
+}
+
+type CatchResult struct {
+	Caught  bool                   `json:"caught"`
+	Pokemon pokeapi.PokemonDetails `json:"pokemon"`
+}
+
+type LocationPage struct {
+	Count    int      `json:"count"`
+	Next     string   `json:"next,omitempty"`
+	Previous string   `json:"previous,omitempty"`
+	Results  []string `json:"results"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	entries, err := s.dex.Query(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	summaries := make([]PokemonSummary, 0, len(entries))
+	for _, entry := range entries {
+		summary := PokemonSummary{
+			ID:       entry.Pokemon.ID,
+			Name:     entry.Pokemon.Name,
+			Types:    []string{},
+			BST:      pokedex.BaseStatTotal(entry.Pokemon),
+			CaughtAt: entry.CaughtAt,
+		}
+		for _, t := range entry.Pokemon.Types {
+			summary.Types = append(summary.Types, t.Type.Name)
+		}
+		summaries = append(summaries, summary)
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) handleInspect(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if resolved, err := s.api.ResolvePokemon(name); err == nil {
+		name = resolved
+	}
+	pokemon, err := s.dex.InspectPokemon(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, pokemon)
+}
+
+func (s *Server) handleCatch(w http.ResponseWriter, r *http.Request) {
+	name, err := s.api.ResolvePokemon(r.PathValue("name"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	pokemon, caught, err := s.dex.CatchPokemon(name)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, CatchResult{Caught: caught, Pokemon: pokemon})
+}
+
+func (s *Server) handleLocations(w http.ResponseWriter, r *http.Request) {
+	offset, err := intParam(r.URL.Query(), "offset", 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := intParam(r.URL.Query(), "limit", defaultPageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if limit <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("limit must be positive"))
+		return
+	}
+	resp, err := s.api.GetLocationsPage(offset, limit)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	page := LocationPage{Count: resp.Count, Results: []string{}}
+	for _, location := range resp.Results {
+		page.Results = append(page.Results, location.Name)
+	}
+	if resp.Next != nil {
+		page.Next = fmt.Sprintf("/locations?offset=%d&limit=%d", offset+limit, limit)
+	}
+	if resp.Previous != nil {
+		page.Previous = fmt.Sprintf("/locations?offset=%d&limit=%d", max(offset-limit, 0), limit)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) handleExplore(w http.ResponseWriter, r *http.Request) {
+	details, err := s.api.GetLocationDetails(r.PathValue("name"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, details)
+}
+
+// parseQuery reads the same filters as the REPL's pokedex command, e.g.
+// ?sort=bst&type=fire&min-stat=attack=80&limit=20&offset=0
+func parseQuery(values url.Values) (pokedex.Query, error) {
+	var query pokedex.Query
+	var err error
+	if sort := values.Get("sort"); sort != "" {
+		if query.Sort, err = pokedex.ParseSortKey(sort); err != nil {
+			return query, err
+		}
+	}
+	query.Types = values["type"]
+	if query.MinStats, err = pokedex.ParseMinStats(values["min-stat"]); err != nil {
+		return query, fmt.Errorf("min-stat: %w", err)
+	}
+	if query.Limit, err = intParam(values, "limit", 0); err != nil {
+		return query, err
+	}
+	if query.Offset, err = intParam(values, "offset", 0); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+func intParam(values url.Values, name string, fallback int) (int, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", name, raw)
+	}
+	return n, nil
+}
+
+func writeAPIError(w http.ResponseWriter, err error) {
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeError(w, http.StatusBadGateway, err)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+)
+
+type mockAPI struct{}
+
+func (mockAPI) ResolvePokemon(input string) (string, error) {
+	if input == "4" {
+		return "charmander", nil
+	}
+	return input, nil
+}
+
+func (mockAPI) GetPokemon(name string) (pokeapi.PokemonDetails, error) {
+	var pokemon pokeapi.PokemonDetails
+	switch name {
+	case "charmander":
+		err := json.Unmarshal([]byte(`{"id": 4, "name": "charmander", "base_experience": 62,
+			"types": [{"slot": 1, "type": {"name": "fire"}}],
+			"stats": [{"base_stat": 39, "stat": {"name": "hp"}}, {"base_stat": 52, "stat": {"name": "attack"}}]}`), &pokemon)
+		return pokemon, err
+	default:
+		return pokemon, fmt.Errorf("%w: %s", pokeapi.ErrNotFound, name)
+	}
+}
+
+func (mockAPI) GetLocationsPage(offset, limit int) (pokeapi.PaginatedResponse[pokeapi.ListEntry], error) {
+	next := "next"
+	resp := pokeapi.PaginatedResponse[pokeapi.ListEntry]{Count: 3, Next: &next}
+	names := []string{"canalave-city-area", "eterna-city-area", "pastoria-city-area"}
+	for i := offset; i < min(offset+limit, len(names)); i++ {
+		resp.Results = append(resp.Results, pokeapi.ListEntry{Name: names[i]})
+	}
+	return resp, nil
+}
+
+func (mockAPI) GetLocationDetails(name string) (pokeapi.LocationDetails, error) {
+	if name != "canalave-city-area" {
+		return pokeapi.LocationDetails{}, fmt.Errorf("%w: %s", pokeapi.ErrNotFound, name)
+	}
+	return pokeapi.LocationDetails{ID: 1, Name: name}, nil
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	dex := pokedex.NewPokedex(
+		pokedex.WithAPIClient(mockAPI{}),
+		pokedex.WithCatchPolicy(pokedex.CatchPolicyFunc(func(pokeapi.PokemonDetails) bool { return true })),
+	)
+	ts := httptest.NewServer(New(dex, mockAPI{}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func decode[T any](t *testing.T, res *http.Response) T {
+	t.Helper()
+	defer res.Body.Close()
+	var body T
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return body
+}
+
+func TestCatchInspectList(t *testing.T) {
+	ts := newTestServer(t)
+
+	res, err := http.Get(ts.URL + "/pokedex/charmander")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 before catching, got %d", res.StatusCode)
+	}
+
+	res, err = http.Post(ts.URL+"/pokedex/4/catch", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := decode[CatchResult](t, res)
+	if !result.Caught || result.Pokemon.Name != "charmander" {
+		t.Errorf("expected charmander to be caught, got %+v", result)
+	}
+
+	res, err = http.Get(ts.URL + "/pokedex/charmander")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pokemon := decode[pokeapi.PokemonDetails](t, res)
+	if pokemon.ID != 4 {
+		t.Errorf("expected charmander's details, got %+v", pokemon)
+	}
+
+	res, err = http.Get(ts.URL + "/pokedex?type=fire&min-stat=attack=50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := decode[[]PokemonSummary](t, res)
+	if len(list) != 1 || list[0].Name != "charmander" || list[0].BST != 91 {
+		t.Errorf("expected charmander in the list, got %+v", list)
+	}
+
+	res, err = http.Get(ts.URL + "/pokedex?type=water")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list := decode[[]PokemonSummary](t, res); len(list) != 0 {
+		t.Errorf("expected no water types, got %+v", list)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ts := newTestServer(t)
+	cases := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodPost, "/pokedex/missingno/catch", http.StatusNotFound},
+		{http.MethodGet, "/pokedex?sort=weight", http.StatusBadRequest},
+		{http.MethodGet, "/pokedex?min-stat=attack", http.StatusBadRequest},
+		{http.MethodGet, "/locations?limit=0", http.StatusBadRequest},
+		{http.MethodGet, "/locations/nowhere", http.StatusNotFound},
+		{http.MethodDelete, "/pokedex/charmander", http.StatusMethodNotAllowed},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, ts.URL+c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != c.status {
+			t.Errorf("%s %s: expected %d, got %d", c.method, c.path, c.status, res.StatusCode)
+		}
+	}
+}
+
+func TestLocations(t *testing.T) {
+	ts := newTestServer(t)
+
+	res, err := http.Get(ts.URL + "/locations?offset=1&limit=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := decode[LocationPage](t, res)
+	if len(page.Results) != 1 || page.Results[0] != "eterna-city-area" {
+		t.Errorf("expected eterna-city-area, got %+v", page)
+	}
+	if page.Next != "/locations?offset=2&limit=1" {
+		t.Errorf("expected a local next link, got %q", page.Next)
+	}
+
+	res, err = http.Get(ts.URL + "/locations/canalave-city-area")
+	if err != nil {
+		t.Fatal(err)
+	}
+	details := decode[pokeapi.LocationDetails](t, res)
+	if details.Name != "canalave-city-area" {
+		t.Errorf("expected canalave-city-area, got %+v", details)
+	}
+}
@@ -0,0 +1,338 @@
+// Package termimg draws images in a terminal, either with ANSI truecolor
+// half-block characters, which work almost everywhere, or with the sixel
+// and kitty graphics protocols for real pixels.
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// Protocol is a way of drawing images in a terminal.
+type Protocol int
+
+const (
+	// None draws nothing, e.g. when output isn't a terminal.
+	None Protocol = iota
+	// HalfBlocks draws two pixels per character cell with ▀ and truecolor
+	// foreground and background colors.
+	HalfBlocks
+	Sixel
+	Kitty
+)
+
+var protocolNames = []string{"none", "blocks", "sixel", "kitty"}
+
+func (p Protocol) String() string {
+	if int(p) < len(protocolNames) {
+		return protocolNames[p]
+	}
+	return fmt.Sprintf("Protocol(%d)", int(p))
+}
+
+// ParseProtocol parses a protocol name as returned by String.
+func ParseProtocol(name string) (Protocol, error) {
+	for i, n := range protocolNames {
+		if strings.EqualFold(name, n) {
+			return Protocol(i), nil
+		}
+	}
+	return None, fmt.Errorf("unknown graphics protocol %q, expected one of %s", name, strings.Join(protocolNames, ", "))
+}
+
+// Detect guesses the best protocol the terminal supports from environment
+// variables, looked up with getenv. Terminals can't always be identified
+// this way, so it falls back to half-blocks.
+func Detect(getenv func(string) string) Protocol {
+	term, program := getenv("TERM"), getenv("TERM_PROGRAM")
+	switch {
+	case term == "dumb":
+		return None
+	case term == "xterm-kitty" || getenv("KITTY_WINDOW_ID") != "" ||
+		program == "WezTerm" || program == "ghostty" || term == "xterm-ghostty":
+		return Kitty
+	case strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") ||
+		strings.HasPrefix(term, "yaft") || strings.Contains(term, "sixel") ||
+		program == "iTerm.app" || program == "contour":
+		return Sixel
+	}
+	return HalfBlocks
+}
+
+// Options controls how an image is drawn.
+type Options struct {
+	// MaxWidth limits the image's width in character cells for HalfBlocks.
+	// Zero means no limit.
+	MaxWidth int
+	// Scale enlarges the image by a whole factor for the pixel protocols,
+	// whose pixels are much smaller than a character cell.
+	Scale int
+}
+
+// alphaThreshold is the alpha below which a pixel counts as transparent.
+const alphaThreshold = 0x8000
+
+func opaque(c color.Color) bool {
+	_, _, _, a := c.RGBA()
+	return a >= alphaThreshold
+}
+
+// Render draws img to w using p.
+func Render(w io.Writer, img image.Image, p Protocol, opts Options) error {
+	switch p {
+	case None:
+		return nil
+	case HalfBlocks:
+		if bounds := img.Bounds(); opts.MaxWidth > 0 && bounds.Dx() > opts.MaxWidth {
+			img = resize(img, opts.MaxWidth, bounds.Dy()*opts.MaxWidth/bounds.Dx())
+		}
+		return renderHalfBlocks(w, img)
+	case Sixel:
+		return renderSixel(w, scale(img, opts.Scale))
+	case Kitty:
+		return renderKitty(w, scale(img, opts.Scale))
+	}
+	return fmt.Errorf("unknown graphics protocol %v", p)
+}
+
+// Crop trims fully transparent rows and columns from the edges of img.
+// Sprites sit in the middle of a larger transparent canvas.
+func Crop(img image.Image) image.Image {
+	bounds := img.Bounds()
+	crop := image.Rectangle{Min: bounds.Max, Max: bounds.Min}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if !opaque(img.At(x, y)) {
+				continue
+			}
+			crop.Min.X = min(crop.Min.X, x)
+			crop.Min.Y = min(crop.Min.Y, y)
+			crop.Max.X = max(crop.Max.X, x+1)
+			crop.Max.Y = max(crop.Max.Y, y+1)
+		}
+	}
+	if crop.Empty() {
+		return img
+	}
+	out := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	for y := 0; y < crop.Dy(); y++ {
+		for x := 0; x < crop.Dx(); x++ {
+			out.Set(x, y, img.At(crop.Min.X+x, crop.Min.Y+y))
+		}
+	}
+	return out
+}
+
+// resize scales img to width by height with nearest-neighbour sampling,
+// which keeps pixel art crisp.
+func resize(img image.Image, width, height int) image.Image {
+	width, height = max(width, 1), max(height, 1)
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/width
+			sy := bounds.Min.Y + y*bounds.Dy()/height
+			out.Set(x, y, img.At(sx, sy))
+		}
+	}
+	return out
+}
+
+func scale(img image.Image, factor int) image.Image {
+	if factor <= 1 {
+		return img
+	}
+	bounds := img.Bounds()
+	return resize(img, bounds.Dx()*factor, bounds.Dy()*factor)
+}
+
+func rgb(c color.Color) (r, g, b uint8) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return n.R, n.G, n.B
+}
+
+// renderHalfBlocks draws each pair of rows as one line of ▀ characters,
+// the upper pixel in the foreground color and the lower in the background.
+// Transparent pixels keep the terminal's own background.
+func renderHalfBlocks(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	var buf bytes.Buffer
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			top := img.At(x, y)
+			var bottom color.Color = color.Transparent
+			if y+1 < bounds.Max.Y {
+				bottom = img.At(x, y+1)
+			}
+			switch {
+			case opaque(top) && opaque(bottom):
+				tr, tg, tb := rgb(top)
+				br, bg, bb := rgb(bottom)
+				fmt.Fprintf(&buf, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", tr, tg, tb, br, bg, bb)
+			case opaque(top):
+				r, g, b := rgb(top)
+				fmt.Fprintf(&buf, "\x1b[49m\x1b[38;2;%d;%d;%dm▀", r, g, b)
+			case opaque(bottom):
+				r, g, b := rgb(bottom)
+				fmt.Fprintf(&buf, "\x1b[49m\x1b[38;2;%d;%d;%dm▄", r, g, b)
+			default:
+				buf.WriteString("\x1b[0m ")
+			}
+		}
+		buf.WriteString("\x1b[0m\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// maxSixelColors is the size of the sixel color palette.
+const maxSixelColors = 256
+
+// sixelPalette assigns a register to every opaque color in img. Images
+// with more colors than registers are reduced to 6 levels per channel.
+func sixelPalette(img image.Image) (palette []color.NRGBA, index func(color.Color) int) {
+	bounds := img.Bounds()
+	seen := map[color.NRGBA]int{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.At(x, y)
+			if !opaque(c) {
+				continue
+			}
+			n := color.NRGBAModel.Convert(c).(color.NRGBA)
+			n.A = 0xff
+			if _, ok := seen[n]; !ok {
+				seen[n] = len(palette)
+				palette = append(palette, n)
+			}
+		}
+	}
+	if len(palette) <= maxSixelColors {
+		return palette, func(c color.Color) int {
+			n := color.NRGBAModel.Convert(c).(color.NRGBA)
+			n.A = 0xff
+			return seen[n]
+		}
+	}
+
+	palette = palette[:0]
+	for r := 0; r < 6; r++ {
+		for g := 0; g < 6; g++ {
+			for b := 0; b < 6; b++ {
+				palette = append(palette, color.NRGBA{uint8(r * 51), uint8(g * 51), uint8(b * 51), 0xff})
+			}
+		}
+	}
+	return palette, func(c color.Color) int {
+		r, g, b := rgb(c)
+		level := func(v uint8) int { return (int(v) + 25) / 51 }
+		return level(r)*36 + level(g)*6 + level(b)
+	}
+}
+
+// renderSixel encodes img as a sixel image: bands six pixels tall, drawn
+// once per color, with transparent pixels left unpainted.
+func renderSixel(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	palette, index := sixelPalette(img)
+
+	var buf bytes.Buffer
+	// P2=1 leaves unpainted pixels transparent.
+	fmt.Fprintf(&buf, "\x1bP0;1;0q\"1;1;%d;%d", bounds.Dx(), bounds.Dy())
+	for i, c := range palette {
+		fmt.Fprintf(&buf, "#%d;2;%d;%d;%d", i, int(c.R)*100/255, int(c.G)*100/255, int(c.B)*100/255)
+	}
+
+	columns := make([]byte, bounds.Dx())
+	for top := bounds.Min.Y; top < bounds.Max.Y; top += 6 {
+		// Find which colors appear in this band, in palette order.
+		used := make([]bool, len(palette))
+		for y := top; y < min(top+6, bounds.Max.Y); y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				if c := img.At(x, y); opaque(c) {
+					used[index(c)] = true
+				}
+			}
+		}
+		first := true
+		for i := range palette {
+			if !used[i] {
+				continue
+			}
+			for x := range columns {
+				var bits byte
+				for dy := 0; dy < 6 && top+dy < bounds.Max.Y; dy++ {
+					if c := img.At(bounds.Min.X+x, top+dy); opaque(c) && index(c) == i {
+						bits |= 1 << dy
+					}
+				}
+				columns[x] = '?' + bits
+			}
+			if !first {
+				// Return to the start of the band to draw the next color.
+				buf.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&buf, "#%d", i)
+			writeSixelRuns(&buf, columns)
+		}
+		buf.WriteByte('-')
+	}
+	buf.WriteString("\x1b\\")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeSixelRuns writes sixel characters, run-length encoding repeats.
+func writeSixelRuns(buf *bytes.Buffer, columns []byte) {
+	for i := 0; i < len(columns); {
+		j := i
+		for j < len(columns) && columns[j] == columns[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(buf, "!%d%c", n, columns[i])
+		} else {
+			buf.Write(columns[i:j])
+		}
+		i = j
+	}
+}
+
+// kittyChunkSize is the most base64 data the kitty protocol accepts in one
+// escape sequence.
+const kittyChunkSize = 4096
+
+// renderKitty sends img as PNG data with the kitty graphics protocol,
+// split into chunks.
+func renderKitty(w io.Writer, img image.Image) error {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return err
+	}
+	data := base64.StdEncoding.EncodeToString(encoded.Bytes())
+
+	var buf bytes.Buffer
+	for i := 0; i < len(data); i += kittyChunkSize {
+		chunk := data[i:min(i+kittyChunkSize, len(data))]
+		more := 0
+		if i+kittyChunkSize < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&buf, "\x1b_Gf=100,a=T,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			fmt.Fprintf(&buf, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,156 @@
+package termimg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+var (
+	red   = color.NRGBA{255, 0, 0, 255}
+	blue  = color.NRGBA{0, 0, 255, 255}
+	empty = color.NRGBA{}
+)
+
+// testImage builds an image from rows of pixels.
+func testImage(rows ...[]color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x, c := range row {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		env      map[string]string
+		expected Protocol
+	}{
+		{map[string]string{"TERM": "xterm-256color"}, HalfBlocks},
+		{map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, Kitty},
+		{map[string]string{"TERM_PROGRAM": "WezTerm"}, Kitty},
+		{map[string]string{"TERM": "foot"}, Sixel},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, Sixel},
+		{map[string]string{"TERM": "dumb"}, None},
+	}
+	for _, c := range cases {
+		getenv := func(key string) string { return c.env[key] }
+		if got := Detect(getenv); got != c.expected {
+			t.Errorf("Detect(%v): expected %v, got %v", c.env, c.expected, got)
+		}
+	}
+}
+
+func TestParseProtocol(t *testing.T) {
+	for _, p := range []Protocol{None, HalfBlocks, Sixel, Kitty} {
+		got, err := ParseProtocol(strings.ToUpper(p.String()))
+		if err != nil || got != p {
+			t.Errorf("ParseProtocol(%q): expected %v, got %v, %v", p.String(), p, got, err)
+		}
+	}
+	if _, err := ParseProtocol("iterm"); err == nil {
+		t.Errorf("expected an error for an unknown protocol")
+	}
+}
+
+func TestCrop(t *testing.T) {
+	img := testImage(
+		[]color.NRGBA{empty, empty, empty, empty},
+		[]color.NRGBA{empty, red, empty, empty},
+		[]color.NRGBA{empty, empty, blue, empty},
+		[]color.NRGBA{empty, empty, empty, empty},
+	)
+	cropped := Crop(img)
+	if cropped.Bounds() != image.Rect(0, 0, 2, 2) {
+		t.Fatalf("expected a 2x2 image, got %v", cropped.Bounds())
+	}
+	if !opaque(cropped.At(0, 0)) || opaque(cropped.At(1, 0)) || !opaque(cropped.At(1, 1)) {
+		t.Errorf("cropped the wrong pixels")
+	}
+
+	empty := testImage([]color.NRGBA{empty, empty})
+	if Crop(empty).Bounds() != empty.Bounds() {
+		t.Errorf("expected a fully transparent image to be left alone")
+	}
+}
+
+func TestRenderHalfBlocks(t *testing.T) {
+	img := testImage(
+		[]color.NRGBA{red, red, empty},
+		[]color.NRGBA{blue, empty, blue},
+		[]color.NRGBA{red, empty, empty},
+	)
+	var out bytes.Buffer
+	if err := Render(&out, img, HalfBlocks, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀" +
+		"\x1b[49m\x1b[38;2;255;0;0m▀" +
+		"\x1b[49m\x1b[38;2;0;0;255m▄" +
+		"\x1b[0m\n" +
+		"\x1b[49m\x1b[38;2;255;0;0m▀" +
+		"\x1b[0m \x1b[0m \x1b[0m\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	wide := testImage([]color.NRGBA{red, red, red, red}, []color.NRGBA{red, red, red, red})
+	if err := Render(&out, wide, HalfBlocks, Options{MaxWidth: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out.String(), "▀") != 2 {
+		t.Errorf("expected the image to shrink to two columns, got %q", out.String())
+	}
+}
+
+func TestRenderSixel(t *testing.T) {
+	img := testImage(
+		[]color.NRGBA{red, red, red, red, red},
+		[]color.NRGBA{empty, blue, empty, empty, empty},
+	)
+	var out bytes.Buffer
+	if err := Render(&out, img, Sixel, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	// Red fills the top row of the band (bit 0, '@'), blue the second row
+	// of one column (bit 1, 'A').
+	expected := "\x1bP0;1;0q\"1;1;5;2" +
+		"#0;2;100;0;0#1;2;0;0;100" +
+		"#0!5@$#1?A???-" +
+		"\x1b\\"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestRenderKitty(t *testing.T) {
+	// Noise doesn't compress, so the PNG needs several chunks.
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range img.Pix {
+		img.Pix[i] = byte(rng.Uint32())
+	}
+	var out bytes.Buffer
+	if err := Render(&out, img, Kitty, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if !strings.HasPrefix(got, "\x1b_Gf=100,a=T,m=1;") {
+		t.Errorf("expected the first chunk to announce more data, got %q", got[:min(len(got), 40)])
+	}
+	if !strings.Contains(got, "\x1b_Gm=0;") || !strings.HasSuffix(got, "\x1b\\\n") {
+		t.Errorf("expected a final chunk")
+	}
+	for _, chunk := range strings.Split(got, "\x1b\\") {
+		if _, data, ok := strings.Cut(chunk, ";"); ok && len(data) > kittyChunkSize {
+			t.Errorf("chunk of %d bytes exceeds %d", len(data), kittyChunkSize)
+		}
+	}
+}
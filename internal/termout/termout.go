@@ -0,0 +1,328 @@
+// Package termout writes command output for a terminal. It knows whether
+// output is a terminal and how big it is, colors text when that's allowed,
+// lays out lists in columns and pages output too long for the screen.
+// Written anywhere else, output is left plain.
+package termout
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+const (
+	// defaultPager is run when $PAGER isn't set.
+	defaultPager = "less"
+	// lessOptions are used when $LESS isn't set: quit if the output fits,
+	// pass colors through and don't clear the screen afterwards.
+	lessOptions = "FRX"
+	// columnGap separates columns laid out by Columns.
+	columnGap = 2
+)
+
+// Writer writes output, styling and laying it out to suit where it goes.
+// Its fields are detected by Open and may be changed afterwards, e.g. to
+// turn Color off for --no-color.
+type Writer struct {
+	// TTY is whether output goes to a terminal.
+	TTY bool
+	// Width and Height are the terminal's size in cells, or 0 if unknown.
+	Width  int
+	Height int
+	// Color is whether Paint adds ANSI colors.
+	Color bool
+	// Pager is the command Page pipes long output through, or "" to never
+	// page.
+	Pager string
+
+	out  io.Writer
+	file *os.File
+	// buf collects a command's output while Page decides whether it needs
+	// the pager, or is nil.
+	buf *bytes.Buffer
+}
+
+// New returns a Writer for plain output to w: no colors, columns or pager.
+func New(w io.Writer) *Writer {
+	return &Writer{out: w}
+}
+
+// Open returns a Writer for f, detecting whether it's a terminal and its
+// size. Colors are on for terminals unless NO_COLOR is set or TERM is
+// dumb, and long output is paged with $PAGER. Environment variables are
+// looked up with getenv.
+func Open(f *os.File, getenv func(string) string) *Writer {
+	w := New(f)
+	w.file = f
+	w.TTY = term.IsTerminal(int(f.Fd()))
+	if !w.TTY {
+		return w
+	}
+	w.refreshSize()
+	w.Color = getenv("NO_COLOR") == "" && getenv("TERM") != "dumb"
+	w.Pager = cmp.Or(getenv("PAGER"), defaultPager)
+	return w
+}
+
+// refreshSize reads the terminal's size again, which may have changed
+// since the last command.
+func (w *Writer) refreshSize() {
+	if w.file == nil {
+		return
+	}
+	if width, height, err := term.GetSize(int(w.file.Fd())); err == nil {
+		w.Width, w.Height = width, height
+	}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.buf != nil {
+		return w.buf.Write(p)
+	}
+	return w.out.Write(p)
+}
+
+// Page runs fn, which writes a command's output, and shows the output in
+// the pager if it won't fit on the screen. Output that fits, or that isn't
+// going to a terminal, is written as usual.
+func (w *Writer) Page(fn func() error) error {
+	if !w.TTY || w.Pager == "" || w.buf != nil {
+		return fn()
+	}
+	w.refreshSize()
+	w.buf = &bytes.Buffer{}
+	err := fn()
+	buf := w.buf
+	w.buf = nil
+	if buf == nil || buf.Len() == 0 {
+		return err
+	}
+	// Leave a line for the prompt that follows.
+	if w.Height > 0 && w.lines(buf.String()) >= w.Height {
+		if w.page(buf.Bytes()) == nil {
+			return err
+		}
+	}
+	w.out.Write(buf.Bytes())
+	return err
+}
+
+// Live writes output straight to the terminal for the rest of the current
+// command, for output that is redrawn in place or contains images, which a
+// pager can't show.
+func (w *Writer) Live() {
+	if w.buf == nil {
+		return
+	}
+	w.out.Write(w.buf.Bytes())
+	w.buf = nil
+}
+
+// lines counts the screen lines text takes up, including wrapped ones.
+func (w *Writer) lines(text string) int {
+	n := 0
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		n++
+		if width := Width(line); w.Width > 0 && width > w.Width {
+			n += (width - 1) / w.Width
+		}
+	}
+	return n
+}
+
+// page pipes data through the pager. It returns an error only if the pager
+// couldn't be started, so the caller can write data itself.
+func (w *Writer) page(data []byte) error {
+	args := strings.Fields(w.Pager)
+	if len(args) == 0 {
+		return fmt.Errorf("no pager")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = w.out
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS="+lessOptions)
+	}
+	// Ctrl-C belongs to the pager while it runs.
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	cmd.Wait()
+	return nil
+}
+
+// Style is a list of ANSI SGR parameters, such as "1;32" for bold green.
+type Style string
+
+const (
+	Bold        Style = "1"
+	Dim         Style = "2"
+	Red         Style = "31"
+	Green       Style = "32"
+	Yellow      Style = "33"
+	Cyan        Style = "36"
+	BrightGreen Style = "92"
+)
+
+// Color256 is a foreground color from the 256-color palette.
+func Color256(n int) Style {
+	return Style("38;5;" + strconv.Itoa(n))
+}
+
+// RGB is a truecolor foreground color.
+func RGB(r, g, b uint8) Style {
+	return Style(fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
+}
+
+// Hex is a truecolor foreground color written like "#f08030". Malformed
+// colors are no style at all.
+func Hex(hex string) Style {
+	n, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(hex, "#")) != 6 {
+		return ""
+	}
+	return RGB(uint8(n>>16), uint8(n>>8), uint8(n))
+}
+
+// With combines two styles, e.g. Bold.With(Green).
+func (s Style) With(other Style) Style {
+	if s == "" || other == "" {
+		return s + other
+	}
+	return s + ";" + other
+}
+
+// Paint styles s if colors are on.
+func (w *Writer) Paint(s string, style Style) string {
+	if !w.Color || style == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + string(style) + "m" + s + "\x1b[0m"
+}
+
+// Columns writes items in as many columns as fit the terminal, top to
+// bottom then left to right like ls, each line starting with indent. When
+// the width isn't known they're written one per line.
+func (w *Writer) Columns(indent string, items []string) {
+	widest := 0
+	for _, item := range items {
+		widest = max(widest, Width(item))
+	}
+	columns := 1
+	if w.Width > 0 {
+		columns = max((w.Width-Width(indent)+columnGap)/(widest+columnGap), 1)
+	}
+	rows := (len(items) + columns - 1) / max(columns, 1)
+	for row := range rows {
+		var b strings.Builder
+		b.WriteString(indent)
+		for i := row; i < len(items); i += rows {
+			if i+rows < len(items) {
+				b.WriteString(Pad(items[i], widest+columnGap))
+			} else {
+				b.WriteString(items[i])
+			}
+		}
+		fmt.Fprintln(w, b.String())
+	}
+}
+
+// Width is how many cells s takes up on a terminal. ANSI escape sequences
+// take none and East Asian wide characters, as in Japanese names, take two.
+func Width(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			// Skip to the sequence's final byte.
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		width++
+		if wide(r) {
+			width++
+		}
+	}
+	return width
+}
+
+func wide(r rune) bool {
+	switch {
+	case r < 0x1100:
+		return false
+	case r <= 0x115f, // Hangul Jamo
+		r >= 0x2e80 && r <= 0xa4cf && r != 0x303f, // CJK, kana
+		r >= 0xac00 && r <= 0xd7a3,                // Hangul syllables
+		r >= 0xf900 && r <= 0xfaff,                // CJK compatibility ideographs
+		r >= 0xfe30 && r <= 0xfe4f,                // CJK compatibility forms
+		r >= 0xff00 && r <= 0xff60,                // fullwidth forms
+		r >= 0xffe0 && r <= 0xffe6,
+		r >= 0x20000 && r <= 0x3fffd:
+		return true
+	}
+	return false
+}
+
+// Pad fills s with spaces to width cells.
+func Pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-Width(s), 0))
+}
+
+// Truncate cuts s to at most width cells, ending it with "…" if anything
+// was cut. Escape sequences are kept, and reset if s is cut after one.
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used, styled := 0, false
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			start := i
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+			i = min(i+1, len(s))
+			b.WriteString(s[start:i])
+			styled = true
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		cells := 1
+		if wide(r) {
+			cells = 2
+		}
+		if used+cells > width-1 {
+			break
+		}
+		b.WriteString(s[i : i+size])
+		used += cells
+		i += size
+	}
+	b.WriteString("…")
+	if styled {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package termout
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	cases := []struct {
+		s        string
+		expected int
+	}{
+		{"pikachu", 7},
+		{"\x1b[38;2;240;128;48mfire\x1b[0m", 4},
+		{"ピカチュウ", 10},
+		{"████▍", 5},
+		{"", 0},
+	}
+	for _, c := range cases {
+		if got := Width(c.s); got != c.expected {
+			t.Errorf("Width(%q): expected %d, got %d", c.s, c.expected, got)
+		}
+	}
+	if got := Pad("ピカ", 6); got != "ピカ  " {
+		t.Errorf("Pad: expected %q, got %q", "ピカ  ", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		s        string
+		width    int
+		expected string
+	}{
+		{"pikachu", 7, "pikachu"},
+		{"pikachu", 5, "pika…"},
+		{"ピカチュウ", 5, "ピカ…"},
+		{"\x1b[33mpikachu\x1b[0m", 4, "\x1b[33mpik…\x1b[0m"},
+		{"pikachu", 0, ""},
+	}
+	for _, c := range cases {
+		if got := Truncate(c.s, c.width); got != c.expected {
+			t.Errorf("Truncate(%q, %d): expected %q, got %q", c.s, c.width, c.expected, got)
+		}
+	}
+}
+
+func TestPaint(t *testing.T) {
+	w := New(&bytes.Buffer{})
+	if got := w.Paint("fire", Hex("#f08030")); got != "fire" {
+		t.Errorf("expected no color, got %q", got)
+	}
+	w.Color = true
+	cases := []struct {
+		style    Style
+		expected string
+	}{
+		{Hex("#f08030"), "\x1b[38;2;240;128;48mfire\x1b[0m"},
+		{Bold.With(Green), "\x1b[1;32mfire\x1b[0m"},
+		{Color256(208), "\x1b[38;5;208mfire\x1b[0m"},
+		{Hex("orange"), "fire"},
+	}
+	for _, c := range cases {
+		if got := w.Paint("fire", c.style); got != c.expected {
+			t.Errorf("Paint(%q): expected %q, got %q", c.style, c.expected, got)
+		}
+	}
+}
+
+func TestColumns(t *testing.T) {
+	items := []string{"bulbasaur", "ivysaur", "venusaur", "charmander", "charmeleon"}
+	cases := []struct {
+		width    int
+		expected string
+	}{
+		{0, "  bulbasaur\n  ivysaur\n  venusaur\n  charmander\n  charmeleon\n"},
+		{10, "  bulbasaur\n  ivysaur\n  venusaur\n  charmander\n  charmeleon\n"},
+		{30, "  bulbasaur   charmander\n  ivysaur     charmeleon\n  venusaur\n"},
+		{80, "  bulbasaur   ivysaur     venusaur    charmander  charmeleon\n"},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		w := New(&out)
+		w.Width = c.width
+		w.Columns("  ", items)
+		if out.String() != c.expected {
+			t.Errorf("width %d: expected %q, got %q", c.width, c.expected, out.String())
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+	var out bytes.Buffer
+	w := New(&out)
+	w.TTY, w.Height, w.Pager = true, 3, "tr a-z A-Z"
+
+	short := func() error {
+		fmt.Fprintln(w, "one\ntwo")
+		return nil
+	}
+	if err := w.Page(short); err != nil || out.String() != "one\ntwo\n" {
+		t.Errorf("expected short output as written, got %q, %v", out.String(), err)
+	}
+
+	out.Reset()
+	long := func() error {
+		fmt.Fprintln(w, "one\ntwo\nthree")
+		return fmt.Errorf("failed")
+	}
+	if err := w.Page(long); err == nil || out.String() != "ONE\nTWO\nTHREE\n" {
+		t.Errorf("expected long output through the pager, got %q, %v", out.String(), err)
+	}
+
+	out.Reset()
+	w.Width = 4
+	wrapped := func() error {
+		fmt.Fprintln(w, "one two three")
+		return nil
+	}
+	if w.Page(wrapped); !strings.HasPrefix(out.String(), "ONE") {
+		t.Errorf("expected wrapped lines to count, got %q", out.String())
+	}
+
+	out.Reset()
+	live := func() error {
+		fmt.Fprintln(w, "one\ntwo")
+		w.Live()
+		fmt.Fprintln(w, "three\nfour")
+		return nil
+	}
+	if w.Page(live); out.String() != "one\ntwo\nthree\nfour\n" {
+		t.Errorf("expected live output to skip the pager, got %q", out.String())
+	}
+
+	out.Reset()
+	w.Pager = "no-such-pager"
+	if w.Page(long); out.String() != "one\ntwo\nthree\n" {
+		t.Errorf("expected output as written when the pager is missing, got %q", out.String())
+	}
+}
+
+func TestOpen(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	getenv := func(string) string { return "" }
+	if w := Open(f, getenv); w.TTY || w.Color || w.Width != 0 || w.Pager != "" {
+		t.Errorf("expected plain output to a file, got %+v", w)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+// localizeWorkers bounds concurrent requests when localizing a list of names.
+const localizeWorkers = 8
+
+// langOff is the lang setting that shows plain API names.
+const langOff = "off"
+
+// resolveLanguage matches code against the languages the PokeAPI knows,
+// ignoring case, and returns the API's spelling of it (e.g. "ja-Hrkt"). If
+// the language list can't be fetched the code is accepted as typed.
+func resolveLanguage(code string) (string, error) {
+	languages, err := pokeapi.LanguageNames()
+	if err != nil {
+		return code, nil
+	}
+	for _, language := range languages {
+		if strings.EqualFold(language, code) {
+			return language, nil
+		}
+	}
+	return "", fmt.Errorf("no language with code %q.%s", code, didYouMean(code, languages))
+}
+
+// localized reports whether names should be translated at all. With no
+// language set, commands show API names without any extra requests.
+func (c *Config) localized() bool {
+	return c.Lang != ""
+}
+
+// pokemonName returns a Pokemon's display name in the chosen language.
+// Names come from the species, so forms like deoxys-normal fall back to
+// their species' name.
+func (c *Config) pokemonName(name string) string {
+	if !c.localized() {
+		return name
+	}
+	species, err := pokeapi.GetPokemonSpecies(name)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		pokemon, pokemonErr := pokeapi.GetPokemon(name)
+		if pokemonErr != nil {
+			return name
+		}
+		species, err = pokeapi.GetPokemonSpecies(pokemon.Species.Name)
+	}
+	if err != nil {
+		return name
+	}
+	return pokeapi.Localize(species.Names, c.Lang, name)
+}
+
+// areaName returns a location area's display name in the chosen language.
+func (c *Config) areaName(name string) string {
+	if !c.localized() {
+		return name
+	}
+	details, err := pokeapi.GetLocationDetails(name)
+	if err != nil {
+		return name
+	}
+	return pokeapi.Localize(details.Names, c.Lang, name)
+}
+
+// regionName returns a region's display name in the chosen language.
+func (c *Config) regionName(name string) string {
+	if !c.localized() {
+		return name
+	}
+	region, err := pokeapi.GetRegion(name)
+	if err != nil {
+		return name
+	}
+	return pokeapi.Localize(region.Names, c.Lang, name)
+}
+
+// locationName returns a location's display name in the chosen language.
+func (c *Config) locationName(name string) string {
+	if !c.localized() {
+		return name
+	}
+	location, err := pokeapi.GetLocation(name)
+	if err != nil {
+		return name
+	}
+	return pokeapi.Localize(location.Names, c.Lang, name)
+}
+
+// typeName returns a type's display name in the chosen language.
+func (c *Config) typeName(name string) string {
+	if !c.localized() {
+		return name
+	}
+	t, err := pokeapi.GetType(name)
+	if err != nil {
+		return name
+	}
+	return pokeapi.Localize(t.Names, c.Lang, name)
+}
+
+// moveName returns a move's display name in the chosen language.
+func (c *Config) moveName(name string) string {
+	if !c.localized() {
+		return name
+	}
+	move, err := pokeapi.GetMove(name)
+	if err != nil {
+		return name
+	}
+	return pokeapi.Localize(move.Names, c.Lang, name)
+}
+
+// localizeAll applies localize to every name concurrently, keeping order.
+// Each lookup is cached, so later pages and commands are fast.
+func (c *Config) localizeAll(names []string, localize func(string) string) []string {
+	out := make([]string, len(names))
+	if !c.localized() {
+		copy(out, names)
+		return out
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(localizeWorkers, len(names)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				out[j] = localize(names[j])
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return out
+}
+
+// withAPIName shows the API name next to a translated one, so the user
+// knows what to type in later commands.
+func withAPIName(display, name string) string {
+	if display == name {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", display, name)
+}
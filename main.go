@@ -1,140 +1,596 @@
 package main
 
 import (
-	"bufio"
+	"cmp"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
-	"github.com/shamsup/pokedexcli/internal/pokeapi"
-	"github.com/shamsup/pokedexcli/internal/pokedex"
+	"github.com/shamsup/pokedexcli/internal/termimg"
+	"github.com/shamsup/pokedexcli/internal/termout"
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
 )
 
-var commands = map[string]Command{}
+var commands = NewRegistry()
 
-func registerCommand(cmd Command) {
-	commands[cmd.Name] = cmd
+func main() {
+	os.Exit(run())
 }
 
-func main() {
-	sharedConfig := Config{Pokedex: pokedex.NewPokedex(pokedex.PokedexConfig{})}
+// run runs pokedexcli and returns its exit status. Everything returns here,
+// rather than calling os.Exit, so the database is closed.
+func run() int {
+	flags := flag.NewFlagSet("pokedexcli", flag.ExitOnError)
+	lang := flags.String("lang", "", "show names in this language, e.g. ja-Hrkt or fr")
+	db := flags.String("db", databasePath(), "database for your Pokedex and cached PokeAPI data; empty keeps them in memory")
+	noColor := flags.Bool("no-color", false, "don't color output, like setting NO_COLOR")
+	flags.Parse(os.Args[1:])
+
+	dex, closeDB, err := openPokedex(*db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer closeDB()
+
+	if flags.Arg(0) == "serve" {
+		if err := runServe(dex, flags.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if dir := dataDir(); dir != "" {
+		pokeapi.SetAssetDir(filepath.Join(dir, assetDirName))
+	}
+	out := termout.Open(os.Stdout, os.Getenv)
+	if *noColor {
+		out.Color = false
+	}
+	sharedConfig := Config{Pokedex: dex, Out: out, ProfilePath: profilePath()}
+	profile, err := loadProfile(sharedConfig.ProfilePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+	sharedConfig.Profile = profile
+	sharedConfig.Graphics = detectGraphics(out)
+	if *lang != "" {
+		code, err := resolveLanguage(*lang)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+		sharedConfig.Lang = code
+	}
+	if flags.Arg(0) == "tui" {
+		if err := runTUI(&sharedConfig); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		return 0
+	}
+	registerCommands(commands, &sharedConfig)
+	runREPL(&sharedConfig)
+	return 0
+}
+
+// graphicsEnv overrides the detected graphics protocol: none, blocks, sixel
+// or kitty.
+const graphicsEnv = "POKEDEXCLI_GRAPHICS"
+
+// detectGraphics picks how to draw images on out. Images are only drawn to
+// terminals.
+func detectGraphics(out *termout.Writer) termimg.Protocol {
+	if !out.TTY {
+		return termimg.None
+	}
+	if name := os.Getenv(graphicsEnv); name != "" {
+		protocol, err := termimg.ParseProtocol(name)
+		if err == nil {
+			return protocol
+		}
+		fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", graphicsEnv, err)
+	}
+	return termimg.Detect(os.Getenv)
+}
+
+const (
+	categoryGeneral     = "General"
+	categoryExploration = "Exploration"
+	categoryPokemon     = "Pokemon"
+)
 
-	registerCommand(Command{
+// categoryOrder is the order help lists categories in.
+var categoryOrder = []string{categoryGeneral, categoryExploration, categoryPokemon}
+
+func registerCommands(r *Registry, c *Config) {
+	r.Register(Command{
 		Name:        "help",
 		Description: "Displays a help message",
-		Handler:     commandHelp,
-		Config:      &sharedConfig,
+		Category:    categoryGeneral,
+		Aliases:     []string{"?"},
+		Args: []ArgSpec{
+			{Name: "command", Kind: ArgKeyword, Description: "show details for this command", Optional: true},
+		},
+		Examples: []string{"help", "help explore"},
+		Handler:  commandHelp,
+		Config:   c,
 	})
-	registerCommand(Command{
+	r.Register(Command{
 		Name:        "exit",
 		Description: "Exit the Pokedex",
+		Category:    categoryGeneral,
+		Aliases:     []string{"quit"},
 		Handler:     commandExit,
-		Config:      &sharedConfig,
+		Config:      c,
+	})
+	r.Register(Command{
+		Name:        "lang",
+		Description: "Show or change the language names are displayed in",
+		Category:    categoryGeneral,
+		Args: []ArgSpec{
+			{Name: "code", Kind: ArgString, Description: "a PokeAPI language code, or 'off' for API names", Optional: true},
+		},
+		Examples: []string{"lang", "lang ja-Hrkt", "lang fr", "lang off"},
+		Handler:  commandLang,
+		Config:   c,
+	})
+	r.Register(Command{
+		Name:        "profile",
+		Description: "Show or change your trainer profile",
+		Category:    categoryGeneral,
+		Flags: []FlagSpec{
+			{Name: "name", Value: "name", Description: "set your trainer name"},
+		},
+		Examples: []string{"profile", "profile --name Dawn"},
+		Handler:  commandProfile,
+		Config:   c,
+	})
+	r.Register(Command{
+		Name:        "version",
+		Description: "Show or select the game you're playing",
+		Category:    categoryGeneral,
+		Args: []ArgSpec{
+			{Name: "game", Kind: ArgKeyword, Description: "a game version, or 'all' to show every game", Optional: true},
+		},
+		Examples: []string{"version", "version platinum", "version all"},
+		Handler:  commandVersion,
+		Config:   c,
 	})
 
-	registerCommand(Command{
+	r.Register(Command{
+		Name:        "prefetch",
+		Description: "Download PokeAPI data ahead of time so it works offline and inspect can rank stats",
+		Category:    categoryGeneral,
+		Args: []ArgSpec{
+			{Name: "what", Kind: ArgKeyword, Description: "pokemon, locations or all"},
+		},
+		Flags: []FlagSpec{
+			{Name: "concurrency", Value: "n", Description: fmt.Sprintf("requests to make at once (default %d, at most %d)", defaultPrefetchWorkers, maxPrefetchWorkers)},
+		},
+		Examples: []string{"prefetch pokemon", "prefetch all --concurrency 16"},
+		Handler:  commandPrefetch,
+		Config:   c,
+	})
+
+	r.Register(Command{
 		Name:        "map",
 		Description: "List locations from the map. Use 'mapb' to go back or 'map' again to go forward",
-		Handler:     commandMap,
-		Config:      &sharedConfig,
+		Category:    categoryExploration,
+		Flags: []FlagSpec{
+			{Name: "region", Kind: ArgKeyword, Value: "region|all", Description: "only list areas in this region from now on; 'all' lists every area again"},
+		},
+		Examples: []string{"map", "map --region kanto", "map --region all"},
+		Handler:  commandMap,
+		Config:   c,
 	})
 
-	registerCommand(Command{
+	r.Register(Command{
 		Name:        "mapb",
 		Description: "Show the previous page of locations",
+		Category:    categoryExploration,
 		Handler:     commandMapBack,
-		Config:      &sharedConfig,
+		Config:      c,
+	})
+
+	r.Register(Command{
+		Name:        "regions",
+		Description: "List the regions of the Pokemon world",
+		Category:    categoryExploration,
+		Handler:     commandRegions,
+		Config:      c,
 	})
 
-	registerCommand(Command{
+	r.Register(Command{
+		Name:        "region",
+		Description: "List the locations in a region",
+		Category:    categoryExploration,
+		Args: []ArgSpec{
+			{Name: "region", Kind: ArgKeyword, Description: "a region from 'regions'"},
+		},
+		Examples: []string{"region kanto"},
+		Handler:  commandRegion,
+		Config:   c,
+	})
+
+	r.Register(Command{
+		Name:        "areas",
+		Description: "List the areas of a location that can be explored",
+		Category:    categoryExploration,
+		Args: []ArgSpec{
+			{Name: "location", Kind: ArgLocation, Description: "a location from 'region'"},
+		},
+		Examples: []string{"areas pallet-town"},
+		Handler:  commandAreas,
+		Config:   c,
+	})
+
+	r.Register(Command{
 		Name:        "explore",
 		Description: "Explore a location to find Pokemon",
-		Handler:     commandExplore,
+		Category:    categoryExploration,
+		Args: []ArgSpec{
+			{Name: "area", Kind: ArgLocation, Description: "a location area from the map, or #N from the last 'where'"},
+		},
+		Flags: []FlagSpec{
+			{Name: "version", Kind: ArgKeyword, Value: "game|all", Description: "only show encounters in this game (default: the selected version)"},
+			{Name: "method", Kind: ArgKeyword, Value: "walk|surf|old-rod|...", Description: "only show encounters by this method"},
+			{Name: "details", Bool: true, Description: "group encounters by method"},
+		},
+		Examples: []string{
+			"explore canalave-city-area",
+			"explore valley-windworks-area --version platinum --method walk",
+			"explore canalave-city-area --details",
+			"explore #1",
+		},
+		Handler: commandExplore,
+		Config:  c,
 	})
 
-	registerCommand(Command{
+	r.Register(Command{
+		Name:        "where",
+		Description: "List the areas where a Pokemon can be found, best odds first",
+		Category:    categoryExploration,
+		Args: []ArgSpec{
+			{Name: "pokemon", Kind: ArgPokemon, Description: "a name, national dex number or localized name", Rest: true},
+		},
+		Flags: []FlagSpec{
+			{Name: "version", Kind: ArgKeyword, Value: "game|all", Description: "only show encounters in this game (default: the selected version)"},
+		},
+		Examples: []string{"where pikachu", "where magikarp --version pearl"},
+		Handler:  commandWhere,
+		Config:   c,
+	})
+
+	r.Register(Command{
 		Name:        "catch",
 		Description: "Catch a Pokemon",
-		Handler:     commandCatchPokemon,
-		Config:      &sharedConfig,
+		Category:    categoryPokemon,
+		Args: []ArgSpec{
+			{Name: "pokemon", Kind: ArgPokemon, Description: "a name, national dex number or localized name", Rest: true},
+		},
+		Examples: []string{"catch pikachu", "catch 25", "catch Mr. Mime", "catch ピカチュウ"},
+		Handler:  commandCatchPokemon,
+		Config:   c,
 	})
 
-	registerCommand(Command{
+	r.Register(Command{
 		Name:        "inspect",
 		Description: "Inspect a caught Pokemon",
-		Handler:     commandInspectPokemon,
-		Config:      &sharedConfig,
+		Category:    categoryPokemon,
+		Args: []ArgSpec{
+			{Name: "pokemon", Kind: ArgPokemon, Description: "a Pokemon you have caught, by name or number", Rest: true},
+		},
+		Flags: []FlagSpec{
+			{Name: "sprite", Kind: ArgKeyword, Value: "front|back|shiny|game", Description: "draw a sprite, e.g. shiny, back or gen1-red-blue (repeatable)", Repeatable: true},
+		},
+		Examples: []string{"inspect pikachu", "inspect 25", "inspect pikachu --sprite shiny", "inspect pikachu --sprite back --sprite gen4-platinum"},
+		Handler:  commandInspectPokemon,
+		Config:   c,
+	})
+
+	r.Register(Command{
+		Name:        "moves",
+		Description: "List the moves a Pokemon learns in a game",
+		Category:    categoryPokemon,
+		Args: []ArgSpec{
+			{Name: "pokemon", Kind: ArgPokemon, Description: "a name, national dex number or localized name", Rest: true},
+		},
+		Flags: []FlagSpec{
+			{Name: "version", Kind: ArgKeyword, Value: "game", Description: "list moves from this game (default: the selected version, else the latest)"},
+			{Name: "method", Kind: ArgKeyword, Value: "level-up|machine|egg|tutor", Description: "only list moves learned this way"},
+		},
+		Examples: []string{"moves pikachu", "moves pikachu --version red --method level-up"},
+		Handler:  commandMoves,
+		Config:   c,
 	})
 
-	registerCommand(Command{
+	r.Register(Command{
+		Name:        "compare",
+		Description: "Compare Pokemon side by side",
+		Usage:       "compare [--any] <pokemon> <pokemon> [pokemon...]",
+		Category:    categoryPokemon,
+		Args: []ArgSpec{
+			{Name: "pokemon", Kind: ArgPokemon, Description: "two or more Pokemon you have caught, by name or number", Variadic: true},
+		},
+		Flags: []FlagSpec{
+			{Name: "any", Bool: true, Description: "also compare Pokemon you haven't caught"},
+		},
+		Examples: []string{"compare pikachu raichu", "compare bulbasaur charmander squirtle --any"},
+		Handler:  commandCompare,
+		Config:   c,
+	})
+
+	r.Register(Command{
 		Name:        "pokedex",
-		Description: "List all caught Pokemon",
-		Handler:     commandPokedex,
-		Config:      &sharedConfig,
-	})
-
-	scanner := bufio.NewScanner(os.Stdin)
-	for {
-		fmt.Print("Pokedex > ")
-		for scanner.Scan() {
-			words := cleanInput(scanner.Text())
-			if len(words) > 0 {
-				command := words[0]
-				args := words[1:]
-				if cmd, ok := commands[command]; ok {
-					if err := cmd.Handler(cmd.Config, args); err != nil {
-						fmt.Println("Error:", err)
-					}
-				} else {
-					fmt.Print("Unknown command\n")
-				}
-			}
-			fmt.Print("Pokedex > ")
-		}
+		Description: "List caught Pokemon",
+		Category:    categoryPokemon,
+		Aliases:     []string{"dex"},
+		Flags: []FlagSpec{
+			{Name: "sort", Kind: ArgKeyword, Value: "name|id|caught-at|bst", Description: "sort order (default name)"},
+			{Name: "type", Kind: ArgKeyword, Value: "type", Description: "only show Pokemon with this type (repeatable)", Repeatable: true},
+			{Name: "min-stat", Kind: ArgKeyword, Value: "stat=value", Description: "only show Pokemon with at least this base stat (repeatable)", Repeatable: true},
+			{Name: "limit", Value: "n", Description: "show at most this many Pokemon"},
+			{Name: "offset", Value: "n", Description: "skip this many Pokemon"},
+		},
+		Examples: []string{"pokedex", "pokedex --sort bst --type fire --limit 5", "pokedex --min-stat speed=100"},
+		Handler:  commandPokedex,
+		Config:   c,
+	})
+
+	r.Register(Command{
+		Name:        "export",
+		Description: "Save caught Pokemon to a CSV, JSON or Markdown file",
+		Category:    categoryPokemon,
+		Args: []ArgSpec{
+			{Name: "file", Kind: ArgString, Description: "the file to write; its extension picks the format"},
+		},
+		Flags: []FlagSpec{
+			{Name: "format", Kind: ArgKeyword, Value: "csv|json|md", Description: "file format (default: from the file's extension)"},
+		},
+		Examples: []string{"export pokedex.csv", "export progress.md", "export backup --format json"},
+		Handler:  commandExport,
+		Config:   c,
+	})
+
+	r.Register(Command{
+		Name:        "import",
+		Description: "Merge another trainer's exported or saved Pokemon into your Pokedex",
+		Category:    categoryPokemon,
+		Args: []ArgSpec{
+			{Name: "file", Kind: ArgString, Description: "a CSV or JSON file written by export, or a saved Pokedex (--db)"},
+		},
+		Flags: []FlagSpec{
+			{Name: "strategy", Kind: ArgKeyword, Value: "keep|overwrite|union", Description: "for Pokemon you've both caught: keep yours (default), take theirs, or combine them"},
+			{Name: "format", Kind: ArgKeyword, Value: "csv|db|json", Description: "file format (default: from the file)"},
+		},
+		Examples: []string{"import team.json", "import dawn.csv --strategy union", "import barry/pokedex.db"},
+		Handler:  commandImport,
+		Config:   c,
+	})
+}
+
+type Config struct {
+	Next     *string
+	Previous *string
+	Pokedex  *pokedex.Pokedex
+	// Out is where commands write, styled and laid out for the terminal.
+	Out *termout.Writer
+	// SeenLocations holds every location area listed by map or mapb, for
+	// tab completion.
+	SeenLocations []string
+	// Lang is the PokeAPI language code names are shown in, or "" for API
+	// names.
+	Lang string
+	// MapRegion limits map and mapb to one region's areas, or is "" to
+	// page through every area.
+	MapRegion string
+	// regionAreas are MapRegion's areas and regionPage the index of the
+	// page of them shown last, or -1 before the first.
+	regionAreas []string
+	regionPage  int
+	// whereAreas are the areas numbered by the last where, so explore can
+	// take #N instead of a name.
+	whereAreas []string
+	// Profile is the trainer and their selected game, saved to ProfilePath
+	// when it changes. An empty ProfilePath keeps it for this session only.
+	Profile     Profile
+	ProfilePath string
+	// Graphics is how sprites are drawn.
+	Graphics termimg.Protocol
+}
+
+// errExit is returned by the exit command to end the REPL.
+var errExit = errors.New("exit")
+
+func commandExit(c *Config, _ Args) error {
+	fmt.Fprintln(c.Out, "Closing the Pokedex... Goodbye!")
+	return errExit
+}
+
+func commandLang(c *Config, args Args) error {
+	code := args.Arg(0)
+	switch {
+	case code == "" && c.Lang == "":
+		fmt.Fprintln(c.Out, "Showing API names. Use 'lang <code>' to pick a language.")
+		return nil
+	case code == "":
+		fmt.Fprintf(c.Out, "Showing names in %s.\n", c.Lang)
+		return nil
+	case strings.EqualFold(code, langOff):
+		c.Lang = ""
+		fmt.Fprintln(c.Out, "Showing API names.")
+		return nil
 	}
+	code, err := resolveLanguage(code)
+	if err != nil {
+		return err
+	}
+	c.Lang = code
+	fmt.Fprintf(c.Out, "Showing names in %s.\n", c.Lang)
+	return nil
 }
-func cleanInput(text string) []string {
-	words := []string{}
-	for _, word := range strings.Fields(text) {
-		if word != "" {
-			words = append(words, strings.ToLower(word))
+
+func commandProfile(c *Config, args Args) error {
+	if args.Has("name") {
+		c.Profile.Name = args.Flag("name")
+		if err := c.saveProfile(); err != nil {
+			return err
 		}
 	}
-	return words
+	name := c.Profile.Name
+	if name == "" {
+		name = "(unnamed) - set one with 'profile --name <name>'"
+	}
+	fmt.Fprintf(c.Out, "Trainer: %s\n", name)
+	printGame(c)
+	caught, err := c.Pokedex.ListCaughtPokemon()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Out, "Caught: %d Pokemon\n", len(caught))
+	return nil
 }
 
-type Command struct {
-	Name        string
-	Description string
-	Handler     func(c *Config, args []string) error
-	Config      *Config
+func commandVersion(c *Config, args Args) error {
+	switch version := args.Arg(0); version {
+	case "":
+	case allVersions:
+		c.Profile.Version = ""
+		if err := c.saveProfile(); err != nil {
+			return err
+		}
+	default:
+		version, err := resolveVersion(version)
+		if err != nil {
+			return err
+		}
+		c.Profile.Version = version
+		if err := c.saveProfile(); err != nil {
+			return err
+		}
+	}
+	printGame(c)
+	return nil
 }
 
-type Config struct {
-	Next     *string
-	Previous *string
-	Pokedex  pokedex.Pokedex
+func printGame(c *Config) {
+	g, ok := c.game()
+	switch {
+	case c.Profile.Version == "":
+		fmt.Fprintln(c.Out, "Game: all versions - select one with 'version <game>'")
+	case !ok:
+		fmt.Fprintf(c.Out, "Game: %s\n", c.Profile.Version)
+	default:
+		regions := make([]string, len(g.Regions))
+		for i, region := range g.Regions {
+			regions[i] = c.regionName(region)
+		}
+		fmt.Fprintf(c.Out, "Game: %s (%s, generation %d, %s)\n", g.Version, g.Group, g.Generation, strings.Join(regions, ", "))
+	}
 }
 
-func commandExit(c *Config, _ []string) error {
-	fmt.Println("Closing the Pokedex... Goodbye!")
-	os.Exit(0)
+func commandHelp(c *Config, args Args) error {
+	if len(args.Positional) > 0 {
+		cmd, ok := commands.Lookup(args.Arg(0))
+		if !ok {
+			return fmt.Errorf("%w: %s", errUnknownCommand, args.Arg(0))
+		}
+		printCommandHelp(c.Out, cmd)
+		return nil
+	}
+
+	fmt.Fprintln(c.Out, c.Out.Paint("Welcome to the Pokedex!", headingStyle))
+	fmt.Fprintln(c.Out, "Usage:")
+	byCategory := map[string][]Command{}
+	for _, cmd := range commands.Commands() {
+		byCategory[cmd.Category] = append(byCategory[cmd.Category], cmd)
+	}
+	categories := slices.Clone(categoryOrder)
+	for category := range byCategory {
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	for _, category := range categories {
+		cmds := byCategory[category]
+		if len(cmds) == 0 {
+			continue
+		}
+		fmt.Fprintln(c.Out, "")
+		if category == "" {
+			category = "Other"
+		}
+		fmt.Fprintln(c.Out, c.Out.Paint(category+":", headingStyle))
+		w := tabwriter.NewWriter(c.Out, 0, 0, 2, ' ', 0)
+		for _, cmd := range cmds {
+			fmt.Fprintf(w, "  %s\t%s\n", cmd.Name, cmd.Description)
+		}
+		w.Flush()
+	}
+	fmt.Fprintln(c.Out, "")
+	fmt.Fprintln(c.Out, "Run 'help <command>' for details.")
 	return nil
 }
 
-func commandHelp(c *Config, _ []string) error {
-	fmt.Println("Welcome to the Pokedex!")
-	fmt.Println("Usage:")
-	fmt.Println("")
-	for _, cmd := range commands {
-		fmt.Printf("%s: %s\n", cmd.Name, cmd.Description)
+func printCommandHelp(out io.Writer, cmd Command) {
+	fmt.Fprintf(out, "%s: %s\n", cmd.Name, cmd.Description)
+	fmt.Fprintf(out, "Usage: %s\n", cmd.UsageLine())
+	if len(cmd.Aliases) > 0 {
+		fmt.Fprintf(out, "Aliases: %s\n", strings.Join(cmd.Aliases, ", "))
+	}
+	if len(cmd.Args) > 0 {
+		fmt.Fprintln(out, "Arguments:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, arg := range cmd.Args {
+			fmt.Fprintf(w, "  %s\t%s\n", arg.Name, arg.Description)
+		}
+		w.Flush()
+	}
+	if len(cmd.Flags) > 0 {
+		fmt.Fprintln(out, "Flags:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, flag := range cmd.Flags {
+			name := "--" + flag.Name
+			if !flag.Bool {
+				name += " <" + flagValue(flag) + ">"
+			}
+			fmt.Fprintf(w, "  %s\t%s\n", name, flag.Description)
+		}
+		w.Flush()
+	}
+	if len(cmd.Examples) > 0 {
+		fmt.Fprintln(out, "Examples:")
+		for _, example := range cmd.Examples {
+			fmt.Fprintf(out, "  %s\n", example)
+		}
 	}
-	return nil
 }
 
-func commandMap(c *Config, _ []string) error {
+// mapPageSize is how many areas map shows within a region, matching the
+// PokeAPI's default page size used for the full list.
+const mapPageSize = 20
+
+func commandMap(c *Config, args Args) error {
+	if args.Has("region") {
+		if err := setMapRegion(c, args.Flag("region")); err != nil {
+			return err
+		}
+	}
+	if c.MapRegion != "" {
+		return mapRegionPage(c, 1)
+	}
 	if c.Next == nil && c.Previous != nil {
-		fmt.Println("you're on the last page")
+		fmt.Fprintln(c.Out, "you're on the last page")
 		return nil
 	}
 	if c.Next == nil {
@@ -144,27 +600,84 @@ func commandMap(c *Config, _ []string) error {
 	if err != nil {
 		return err
 	}
-	for _, location := range resp.Results {
-		fmt.Println(location.Name)
-	}
+	printLocations(c, entryNames(resp.Results))
 
 	c.Next = resp.Next
 	c.Previous = resp.Previous
 	return nil
 }
 
-func commandMapBack(c *Config, _ []string) error {
+// setMapRegion switches map between one region's areas and every area,
+// starting again from the first page.
+func setMapRegion(c *Config, region string) error {
+	if region == "all" {
+		c.MapRegion = ""
+		c.Next, c.Previous = nil, nil
+		return nil
+	}
+	areas, err := pokeapi.RegionAreas(region)
+	if err != nil {
+		return notFound(err, "region", region, pokeapi.RegionNames)
+	}
+	c.MapRegion = region
+	c.regionAreas = areas
+	c.regionPage = -1
+	return nil
+}
+
+// mapRegionPage shows the next (step 1) or previous (step -1) page of the
+// map region's areas.
+func mapRegionPage(c *Config, step int) error {
+	page := c.regionPage + step
+	switch {
+	case page < 0:
+		fmt.Fprintln(c.Out, "you're on the first page")
+		return nil
+	case len(c.regionAreas) == 0:
+		fmt.Fprintf(c.Out, "%s has no location areas\n", c.regionName(c.MapRegion))
+		return nil
+	case page*mapPageSize >= len(c.regionAreas):
+		fmt.Fprintln(c.Out, "you're on the last page")
+		return nil
+	}
+	c.regionPage = page
+	start := page * mapPageSize
+	printLocations(c, c.regionAreas[start:min(start+mapPageSize, len(c.regionAreas))])
+	return nil
+}
+
+func entryNames(entries []pokeapi.ListEntry) []string {
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	return names
+}
+
+func printLocations(c *Config, names []string) {
+	items := c.localizeAll(names, c.areaName)
+	for i, name := range names {
+		items[i] = withAPIName(items[i], name)
+		if !slices.Contains(c.SeenLocations, name) {
+			c.SeenLocations = append(c.SeenLocations, name)
+		}
+	}
+	c.Out.Columns("", items)
+}
+
+func commandMapBack(c *Config, _ Args) error {
+	if c.MapRegion != "" {
+		return mapRegionPage(c, -1)
+	}
 	if c.Previous == nil {
-		fmt.Println("you're on the first page")
+		fmt.Fprintln(c.Out, "you're on the first page")
 		return nil
 	}
 	resp, err := pokeapi.GetLocations(*c.Previous)
 	if err != nil {
 		return err
 	}
-	for _, location := range resp.Results {
-		fmt.Println(location.Name)
-	}
+	printLocations(c, entryNames(resp.Results))
 
 	c.Next = resp.Next
 	c.Previous = resp.Previous
@@ -172,71 +685,435 @@ func commandMapBack(c *Config, _ []string) error {
 	return nil
 }
 
-func commandExplore(_ *Config, args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("expected location name")
+func commandRegions(c *Config, _ Args) error {
+	regions, err := pokeapi.RegionNames()
+	if err != nil {
+		return err
+	}
+	items := c.localizeAll(regions, c.regionName)
+	for i, region := range regions {
+		items[i] = withAPIName(items[i], region)
+	}
+	c.Out.Columns("", items)
+	return nil
+}
+
+func commandRegion(c *Config, args Args) error {
+	name := args.Arg(0)
+	region, err := pokeapi.GetRegion(name)
+	if err != nil {
+		return notFound(err, "region", name, pokeapi.RegionNames)
+	}
+	names := make([]string, len(region.Locations))
+	for i, location := range region.Locations {
+		names[i] = location.Name
+	}
+	display := name
+	if c.localized() {
+		display = pokeapi.Localize(region.Names, c.Lang, name)
+	}
+	fmt.Fprintf(c.Out, "Locations in %s:\n", display)
+	items := c.localizeAll(names, c.locationName)
+	for i, name := range names {
+		items[i] = "- " + withAPIName(items[i], name)
+	}
+	c.Out.Columns("  ", items)
+	return nil
+}
+
+func commandAreas(c *Config, args Args) error {
+	name := args.Arg(0)
+	location, err := pokeapi.GetLocation(name)
+	if err != nil {
+		return notFound(err, "location", name, pokeapi.LocationNames)
+	}
+	display := name
+	if c.localized() {
+		display = pokeapi.Localize(location.Names, c.Lang, name)
+	}
+	if len(location.Areas) == 0 {
+		fmt.Fprintf(c.Out, "%s has no areas to explore\n", display)
+		return nil
+	}
+	names := make([]string, len(location.Areas))
+	for i, area := range location.Areas {
+		names[i] = area.Name
+	}
+	fmt.Fprintf(c.Out, "Areas in %s:\n", display)
+	items := c.localizeAll(names, c.areaName)
+	for i, name := range names {
+		items[i] = "- " + withAPIName(items[i], name)
+		if !slices.Contains(c.SeenLocations, name) {
+			c.SeenLocations = append(c.SeenLocations, name)
+		}
+	}
+	c.Out.Columns("  ", items)
+	return nil
+}
+
+func commandExplore(c *Config, args Args) error {
+	location := args.Arg(0)
+	if strings.HasPrefix(location, "#") {
+		n, err := strconv.Atoi(location[1:])
+		if err != nil || n < 1 || n > len(c.whereAreas) {
+			return fmt.Errorf("no area %s in the last 'where' listing", location)
+		}
+		location = c.whereAreas[n-1]
 	}
-	location := args[0]
-	fmt.Printf("Exploring %s...\n", location)
 	details, err := pokeapi.GetLocationDetails(location)
 	if err != nil {
-		fmt.Println("Error:", err)
-		return err
+		return notFound(err, "location area", location, pokeapi.LocationAreaNames)
+	}
+	display := location
+	if c.localized() {
+		display = pokeapi.Localize(details.Names, c.Lang, location)
+	}
+	fmt.Fprintf(c.Out, "Exploring %s...\n", display)
+	version := c.versionFlag(args)
+	if args.Has("version") || args.Has("method") || args.Has("details") {
+		return exploreEncounters(c, details, args, version)
 	}
-	fmt.Println("Found Pokemon:")
+	var names []string
 	for _, encounter := range details.PokemonEncounters {
-		fmt.Printf("  - %s\n", encounter.Pokemon.Name)
+		if version != "" && len(summarizeEncounters(encounter.Pokemon.Name, encounter.VersionDetails, version, "")) == 0 {
+			continue
+		}
+		names = append(names, encounter.Pokemon.Name)
+	}
+	if len(names) == 0 && version != "" {
+		fmt.Fprintf(c.Out, "No Pokemon are found here in %s\n", version)
+		return nil
+	}
+	fmt.Fprintln(c.Out, "Found Pokemon:")
+	items := c.localizeAll(names, c.pokemonName)
+	for i := range items {
+		items[i] = "- " + items[i]
 	}
+	c.Out.Columns("  ", items)
 	return nil
 }
 
-func commandCatchPokemon(c *Config, args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("expected pokemon name")
+// exploreEncounters prints the area's encounters as a table of chances,
+// levels and conditions, optionally grouped by method.
+func exploreEncounters(c *Config, details pokeapi.LocationDetails, args Args, version string) error {
+	method := args.Flag("method")
+	if versions := encounterVersions(details); args.Has("version") && version != "" && !slices.Contains(versions, version) {
+		return fmt.Errorf("no encounters here in %q.%s", version, didYouMean(version, versions))
 	}
-	pokemon := args[0]
-	fmt.Printf("Throwing a Pokeball at %s...\n", pokemon)
-	_, caught, err := c.Pokedex.CatchPokemon(pokemon)
+	if methods := encounterMethods(details); method != "" && !slices.Contains(methods, method) {
+		return fmt.Errorf("no encounters here by %q.%s", method, didYouMean(method, methods))
+	}
+
+	var rows []encounterRow
+	var names []string
+	for _, encounter := range details.PokemonEncounters {
+		found := summarizeEncounters(encounter.Pokemon.Name, encounter.VersionDetails, version, method)
+		if len(found) > 0 {
+			names = append(names, encounter.Pokemon.Name)
+		}
+		rows = append(rows, found...)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(c.Out, "No encounters match")
+		return nil
+	}
+	displayNames := map[string]string{}
+	for i, display := range c.localizeAll(names, c.pokemonName) {
+		displayNames[names[i]] = display
+	}
+	for i := range rows {
+		rows[i].Name = displayNames[rows[i].Name]
+	}
+	sortEncounters(rows)
+
+	table := encounterTable{NameHeader: "POKEMON", ShowMethod: method == "", ShowVersions: version == ""}
+	if !args.Bool("details") {
+		table.print(c.Out, rows)
+		return nil
+	}
+	table.ShowMethod = false
+	for _, m := range encounterMethods(details) {
+		var group []encounterRow
+		for _, row := range rows {
+			if row.Method == m {
+				group = append(group, row)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		heading := m
+		if rate := methodRate(details, m, version); rate != "" {
+			heading += " (" + rate + ")"
+		}
+		fmt.Fprintf(c.Out, "%s:\n", heading)
+		table.print(c.Out, group)
+	}
+	return nil
+}
+
+func commandWhere(c *Config, args Args) error {
+	pokemon, err := pokeapi.ResolvePokemon(args.Arg(0))
+	if err != nil {
+		return notFound(err, "pokemon", args.Arg(0), pokeapi.PokemonNames)
+	}
+	encounters, err := pokeapi.GetPokemonEncounters(pokemon)
+	if err != nil {
+		return err
+	}
+	version := c.versionFlag(args)
+	var rows []encounterRow
+	for _, encounter := range encounters {
+		rows = append(rows, summarizeEncounters(encounter.LocationArea.Name, encounter.VersionDetails, version, "")...)
+	}
+	display := c.pokemonName(pokemon)
+	if len(rows) == 0 {
+		if version != "" {
+			fmt.Fprintf(c.Out, "%s can't be found in the wild in %s\n", display, version)
+		} else {
+			fmt.Fprintf(c.Out, "%s can't be found in the wild\n", display)
+		}
+		return nil
+	}
+	sortByChance(rows)
+
+	c.whereAreas = make([]string, len(rows))
+	areas := make([]string, len(rows))
+	for i, row := range rows {
+		c.whereAreas[i] = row.Name
+		areas[i] = row.Name
+	}
+	for i, area := range c.localizeAll(areas, c.areaName) {
+		rows[i].Name = withAPIName(area, areas[i])
+	}
+	fmt.Fprintf(c.Out, "%s can be found in:\n", display)
+	encounterTable{NameHeader: "AREA", ShowMethod: true, ShowVersions: version == "", Numbered: true}.print(c.Out, rows)
+	fmt.Fprintln(c.Out, "Use 'explore #N' to explore one of these areas.")
+	return nil
+}
+
+// learnMethodOrder is the order moves lists learn methods in. Other
+// methods follow alphabetically.
+var learnMethodOrder = []string{"level-up", "machine", "egg", "tutor"}
+
+type learnedMove struct {
+	Move   string
+	Method string
+	Level  int
+}
+
+func commandMoves(c *Config, args Args) error {
+	name, err := pokeapi.ResolvePokemon(args.Arg(0))
 	if err != nil {
-		// fmt.Printf("We had trouble finding a %s to catch. Are you sure they're real?", pokemon)
+		return notFound(err, "pokemon", args.Arg(0), pokeapi.PokemonNames)
+	}
+	pokemon, err := pokeapi.GetPokemon(name)
+	if err != nil {
+		return err
+	}
+
+	var group string
+	if version := c.versionFlag(args); version != "" {
+		g, ok := lookupGame(version)
+		if !ok {
+			if _, err := resolveVersion(version); err != nil {
+				return err
+			}
+			return fmt.Errorf("couldn't look up game version %q", version)
+		}
+		group = g.Group
+	} else if group, err = latestVersionGroup(pokemon); err != nil {
 		return err
 	}
+
+	method := args.Flag("method")
+	var moves []learnedMove
+	for _, move := range pokemon.Moves {
+		for _, details := range move.VersionGroupDetails {
+			if details.VersionGroup.Name != group || (method != "" && details.MoveLearnMethod.Name != method) {
+				continue
+			}
+			moves = append(moves, learnedMove{move.Move.Name, details.MoveLearnMethod.Name, details.LevelLearnedAt})
+		}
+	}
+	display := c.pokemonName(name)
+	if len(moves) == 0 {
+		fmt.Fprintf(c.Out, "%s learns no moves in %s\n", display, group)
+		return nil
+	}
+	slices.SortFunc(moves, func(a, b learnedMove) int {
+		return cmp.Or(
+			cmp.Compare(methodRank(a.Method), methodRank(b.Method)),
+			strings.Compare(a.Method, b.Method),
+			cmp.Compare(a.Level, b.Level),
+			strings.Compare(a.Move, b.Move),
+		)
+	})
+	names := make([]string, len(moves))
+	for i, move := range moves {
+		names[i] = move.Move
+	}
+	names = c.localizeAll(names, c.moveName)
+
+	fmt.Fprintf(c.Out, "Moves %s learns in %s:\n", display, group)
+	w := tabwriter.NewWriter(c.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  LEVEL\tMOVE\tMETHOD")
+	for i, move := range moves {
+		level := "-"
+		if move.Level > 0 {
+			level = strconv.Itoa(move.Level)
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", level, names[i], move.Method)
+	}
+	return w.Flush()
+}
+
+func methodRank(method string) int {
+	if i := slices.Index(learnMethodOrder, method); i >= 0 {
+		return i
+	}
+	return len(learnMethodOrder)
+}
+
+// latestVersionGroup returns the most recent version group a Pokemon
+// learns moves in.
+func latestVersionGroup(pokemon pokeapi.PokemonDetails) (string, error) {
+	var groups []string
+	for _, move := range pokemon.Moves {
+		for _, details := range move.VersionGroupDetails {
+			if !slices.Contains(groups, details.VersionGroup.Name) {
+				groups = append(groups, details.VersionGroup.Name)
+			}
+		}
+	}
+	latest, order := "", -1
+	for _, name := range groups {
+		group, err := pokeapi.GetVersionGroup(name)
+		if err != nil {
+			return "", err
+		}
+		if group.Order > order {
+			latest, order = name, group.Order
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("%s learns no moves", pokemon.Name)
+	}
+	return latest, nil
+}
+
+func commandCatchPokemon(c *Config, args Args) error {
+	pokemon, err := pokeapi.ResolvePokemon(args.Arg(0))
+	if err != nil {
+		return notFound(err, "pokemon", args.Arg(0), pokeapi.PokemonNames)
+	}
+	display := c.pokemonName(pokemon)
+	if version := c.Profile.Version; version != "" {
+		details, err := pokeapi.GetPokemon(pokemon)
+		if err != nil {
+			return notFound(err, "pokemon", pokemon, pokeapi.PokemonNames)
+		}
+		if ok, err := pokeapi.InVersion(details, version); err == nil && !ok {
+			return fmt.Errorf("%s isn't in %s. Use 'version all' to catch from every game", display, version)
+		}
+	}
+	fmt.Fprintf(c.Out, "Throwing a Pokeball at %s...\n", display)
+	_, caught, err := c.Pokedex.CatchPokemon(pokemon)
+	if err != nil {
+		return notFound(err, "pokemon", pokemon, pokeapi.PokemonNames)
+	}
 	if caught {
-		fmt.Printf("%s was caught!\n", pokemon)
+		fmt.Fprintln(c.Out, c.Out.Paint(display+" was caught!", caughtStyle))
 	} else {
-		fmt.Printf("%s got away...\n", pokemon)
+		fmt.Fprintln(c.Out, c.Out.Paint(display+" got away...", termout.Dim))
 	}
 	return nil
 }
 
-func commandInspectPokemon(c *Config, args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("expected pokemon name")
+func commandInspectPokemon(c *Config, args Args) error {
+	name := args.Arg(0)
+	if resolved, err := pokeapi.ResolvePokemon(name); err == nil {
+		name = resolved
 	}
-	name := args[0]
 	pokemon, err := c.Pokedex.InspectPokemon(name)
 	if err != nil {
-		fmt.Println("you have no caught that pokemon")
+		caught, _ := c.Pokedex.ListCaughtPokemon()
+		fmt.Fprintf(c.Out, "you have no caught that pokemon.%s\n", didYouMean(name, caught))
 		return nil
 	}
-	fmt.Printf("Name: %s\n", pokemon.Name)
-	fmt.Printf("Height: %d\n", pokemon.Height)
-	fmt.Printf("Weight: %d\n", pokemon.Weight)
-	fmt.Printf("Stats:\n")
-	for _, stat := range pokemon.Stats {
-		fmt.Printf("  - %s: %d\n", stat.Stat.Name, stat.BaseStat)
+	fmt.Fprintf(c.Out, "Name: %s\n", c.pokemonName(pokemon.Name))
+	fmt.Fprintf(c.Out, "Height: %d\n", pokemon.Height)
+	fmt.Fprintf(c.Out, "Weight: %d\n", pokemon.Weight)
+	c.printStats(pokemon)
+	fmt.Fprintf(c.Out, "Types:\n")
+	for _, t := range c.typeLabels(c.typeNames(pokemon)) {
+		fmt.Fprintf(c.Out, "  - %s\n", t)
 	}
-	fmt.Printf("Types:\n")
-	for _, t := range pokemon.Types {
-		fmt.Printf("  - %s\n", t.Type.Name)
+
+	if !args.Has("sprite") {
+		return nil
 	}
-	return nil
+	choice, err := c.parseSpriteChoice(args.Flags("sprite"))
+	if err != nil {
+		return err
+	}
+	sprite, note := choice.spriteURL(pokemon)
+	if note != "" {
+		fmt.Fprintln(c.Out, note)
+	}
+	if sprite == "" {
+		return nil
+	}
+	fmt.Fprintf(c.Out, "Sprite: %s\n", sprite)
+	return c.drawSprite(sprite)
 }
 
-func commandPokedex(c *Config, _ []string) error {
-	pokemon := c.Pokedex.ListCaughtPokemon()
-	for _, p := range pokemon {
-		fmt.Printf(" - %s\n", p)
+func commandPokedex(c *Config, args Args) error {
+	query, err := parsePokedexQuery(args)
+	if err != nil {
+		return err
+	}
+	entries, err := c.Pokedex.Query(query)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(c.Out, "No caught Pokemon match")
+		return nil
+	}
+	for _, entry := range entries {
+		types := c.typeLabels(c.typeNames(entry.Pokemon))
+		fmt.Fprintf(c.Out, " - #%03d %s (%s) BST %d\n",
+			entry.Pokemon.ID,
+			c.pokemonName(entry.Pokemon.Name),
+			strings.Join(types, "/"),
+			pokedex.BaseStatTotal(entry.Pokemon),
+		)
 	}
 	return nil
 }
+
+// parsePokedexQuery builds a query from the pokedex command's flags.
+func parsePokedexQuery(args Args) (pokedex.Query, error) {
+	var query pokedex.Query
+	var err error
+	if args.Has("sort") {
+		if query.Sort, err = pokedex.ParseSortKey(args.Flag("sort")); err != nil {
+			return query, err
+		}
+	}
+	query.Types = args.Flags("type")
+	if query.MinStats, err = pokedex.ParseMinStats(args.Flags("min-stat")); err != nil {
+		return query, err
+	}
+	if query.Limit, err = args.Int("limit", 0); err != nil {
+		return query, err
+	}
+	if query.Offset, err = args.Int("offset", 0); err != nil {
+		return query, err
+	}
+	if query.Limit < 0 || query.Offset < 0 {
+		return query, fmt.Errorf("limit and offset must not be negative")
+	}
+	return query, nil
+}
@@ -0,0 +1,137 @@
+package pokeapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shamsup/pokedexcli/internal/pokecache"
+)
+
+// assetMaxAge is how long a downloaded asset is used before asking the
+// server whether it changed. Sprites and cries almost never do.
+const assetMaxAge = 7 * 24 * time.Hour
+
+// Asset is a file linked from the PokeAPI, such as a sprite or a cry.
+type Asset struct {
+	Data        []byte
+	ContentType string
+}
+
+// AssetCache downloads assets and keeps them in memory and, optionally, on
+// disk. Saved assets are revalidated with conditional requests once they
+// are older than the cache's max age, and used as they are when the server
+// can't be reached, so assets work offline after the first download.
+type AssetCache struct {
+	disk   *pokecache.DiskCache
+	maxAge time.Duration
+
+	mu     sync.Mutex
+	memory map[string]Asset
+}
+
+// NewAssetCache returns a cache that saves assets in dir, or only in memory
+// if dir is empty. Saved assets younger than maxAge aren't revalidated.
+func NewAssetCache(dir string, maxAge time.Duration) *AssetCache {
+	a := &AssetCache{maxAge: maxAge, memory: map[string]Asset{}}
+	if dir != "" {
+		disk := pokecache.NewDiskCache(dir)
+		a.disk = &disk
+	}
+	return a
+}
+
+var assets = NewAssetCache("", assetMaxAge)
+
+// SetAssetDir makes GetAsset save assets in dir. It must not be called
+// while requests are in flight.
+func SetAssetDir(dir string) {
+	assets = NewAssetCache(dir, assetMaxAge)
+}
+
+// GetAsset downloads a file linked from the PokeAPI, such as a sprite or a
+// cry, through the asset cache.
+func GetAsset(url string) (Asset, error) {
+	return assets.Get(url)
+}
+
+// Get returns the asset at url, from the cache if possible.
+func (a *AssetCache) Get(url string) (Asset, error) {
+	if url == "" {
+		return Asset{}, fmt.Errorf("%w: empty asset url", ErrNotFound)
+	}
+	a.mu.Lock()
+	asset, ok := a.memory[url]
+	a.mu.Unlock()
+	if ok {
+		return asset, nil
+	}
+
+	var saved pokecache.Blob
+	var haveSaved bool
+	if a.disk != nil {
+		saved, haveSaved = a.disk.Get(url)
+	}
+	if haveSaved && time.Since(saved.FetchedAt) < a.maxAge {
+		return a.remember(url, saved), nil
+	}
+
+	header := http.Header{}
+	if haveSaved && saved.ETag != "" {
+		header.Set("If-None-Match", saved.ETag)
+	}
+	if haveSaved && saved.LastModified != "" {
+		header.Set("If-Modified-Since", saved.LastModified)
+	}
+	res, err := get(url, header)
+	if err != nil {
+		if haveSaved {
+			return a.remember(url, saved), nil
+		}
+		return Asset{}, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && haveSaved:
+		// Failing to note the revalidation only means it happens again.
+		a.disk.Touch(url, time.Now())
+		return a.remember(url, saved), nil
+	case res.StatusCode == http.StatusNotFound:
+		return Asset{}, fmt.Errorf("%w: %s", ErrNotFound, url)
+	case res.StatusCode != http.StatusOK:
+		if haveSaved {
+			return a.remember(url, saved), nil
+		}
+		return Asset{}, fmt.Errorf("error: %v %v", res.StatusCode, res.Status)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Asset{}, fmt.Errorf("error: %v", err)
+	}
+	blob := pokecache.Blob{
+		Data:         data,
+		URL:          url,
+		ContentType:  res.Header.Get("Content-Type"),
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+	}
+	if a.disk != nil {
+		// The asset is still usable if it can't be saved; it will just be
+		// downloaded again next time.
+		a.disk.Add(blob)
+	}
+	return a.remember(url, blob), nil
+}
+
+func (a *AssetCache) remember(url string, blob pokecache.Blob) Asset {
+	asset := Asset{Data: blob.Data, ContentType: blob.ContentType}
+	a.mu.Lock()
+	a.memory[url] = asset
+	a.mu.Unlock()
+	return asset
+}
@@ -0,0 +1,55 @@
+package pokeapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// requestTimeout bounds a single request, including reading the body.
+	requestTimeout = 20 * time.Second
+	// maxAttempts is how many times a request is tried before giving up.
+	maxAttempts = 3
+	// retryDelay is the wait before the first retry; it doubles each time.
+	retryDelay = 250 * time.Millisecond
+)
+
+var client = &http.Client{Timeout: requestTimeout}
+
+// get sends a GET request with the package's retry and timeout policy.
+// Network errors, rate limiting and server errors are retried with
+// backoff; other responses, including 404s, are returned as they are. The
+// caller closes the response body.
+func get(url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		res, err := client.Do(req)
+		if attempt == maxAttempts || !retryable(res, err) {
+			if err != nil {
+				return nil, fmt.Errorf("error: %v", err)
+			}
+			return res, nil
+		}
+		if res != nil {
+			res.Body.Close()
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func retryable(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
@@ -0,0 +1,32 @@
+package pokeapi
+
+// DefensiveMatchups returns how much damage attacks of each type do to a
+// Pokemon with the given types, as a multiplier such as 2 or 0.25. Types
+// that do normal damage are left out.
+func DefensiveMatchups(types []string) (map[string]float64, error) {
+	details, err := fetchAll(types, GetType)
+	if err != nil {
+		return nil, err
+	}
+	multipliers := map[string]float64{}
+	scale := func(attackers []NamedResource, factor float64) {
+		for _, attacker := range attackers {
+			if _, ok := multipliers[attacker.Name]; !ok {
+				multipliers[attacker.Name] = 1
+			}
+			multipliers[attacker.Name] *= factor
+		}
+	}
+	for _, t := range details {
+		scale(t.DamageRelations.DoubleDamageFrom, 2)
+		scale(t.DamageRelations.HalfDamageFrom, 0.5)
+		scale(t.DamageRelations.NoDamageFrom, 0)
+	}
+	// A weakness and a resistance cancel out.
+	for name, multiplier := range multipliers {
+		if multiplier == 1 {
+			delete(multipliers, name)
+		}
+	}
+	return multipliers, nil
+}
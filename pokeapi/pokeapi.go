@@ -2,9 +2,13 @@ package pokeapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/shamsup/pokedexcli/internal/pokecache"
@@ -12,6 +16,18 @@ import (
 
 var baseURL = "https://pokeapi.co/api/v2/"
 
+// SetBaseURL points the package at a different PokeAPI host, such as a test
+// server, and returns the previous base URL. It must not be called while
+// requests are in flight.
+func SetBaseURL(url string) string {
+	previous := baseURL
+	baseURL = url
+	return previous
+}
+
+// ErrNotFound is returned when the PokeAPI has no resource at the requested URL.
+var ErrNotFound = errors.New("not found")
+
 type PaginatedResponse[T any] struct {
 	Count    int     `json:"count"`
 	Next     *string `json:"next"`
@@ -26,6 +42,48 @@ type ListEntry struct {
 
 var cache = pokecache.NewCache(5 * time.Minute)
 
+// How long a saved API response is used before it's fetched again. Lists
+// grow when new Pokemon or locations are added, so they expire sooner than
+// the resources in them, which rarely change.
+const (
+	responseMaxAge = 30 * 24 * time.Hour
+	listMaxAge     = 24 * time.Hour
+)
+
+// ResponseCache keeps raw API responses by URL along with when they were
+// fetched.
+type ResponseCache interface {
+	Get(url string) (body []byte, fetchedAt time.Time, ok bool)
+	Add(url string, body []byte)
+}
+
+// maxAge is how long the response at url may be used from the persistent
+// cache. List URLs end in the resource with a slash or have a query.
+func maxAge(url string) time.Duration {
+	if strings.HasSuffix(url, "/") || strings.Contains(url, "?") {
+		return listMaxAge
+	}
+	return responseMaxAge
+}
+
+// persistent backs the in-memory cache with storage that outlives the
+// process. It is nil when responses are only kept in memory.
+var persistent ResponseCache
+
+// SetPersistentCache makes API responses outlive the process by saving
+// them in c as well as in memory, and returns the previous one. A nil c
+// keeps responses in memory only. It must not be called while requests are
+// in flight.
+func SetPersistentCache(c ResponseCache) ResponseCache {
+	previous := persistent
+	persistent = c
+	// The stats index belongs to the cache it was loaded from.
+	statsIndexMu.Lock()
+	statsIndex = nil
+	statsIndexMu.Unlock()
+	return previous
+}
+
 func GetLocations(overrideUrl string) (PaginatedResponse[ListEntry], error) {
 	url := baseURL + "location-area/"
 	if overrideUrl != "" {
@@ -35,6 +93,12 @@ func GetLocations(overrideUrl string) (PaginatedResponse[ListEntry], error) {
 	return result, err
 }
 
+// GetLocationsPage fetches one page of location areas starting at offset.
+func GetLocationsPage(offset, limit int) (PaginatedResponse[ListEntry], error) {
+	url := fmt.Sprintf("%slocation-area/?offset=%d&limit=%d", baseURL, offset, limit)
+	return cachedFetch[PaginatedResponse[ListEntry]](url)
+}
+
 func GetLocationDetails(location string) (LocationDetails, error) {
 	url := baseURL + "location-area/" + location
 	result, err := cachedFetch[LocationDetails](url)
@@ -47,6 +111,158 @@ func GetPokemon(pokemon string) (PokemonDetails, error) {
 	return result, err
 }
 
+// listPageSize is how many entries to request per page when walking a
+// whole list endpoint.
+const listPageSize = 200
+
+// fetchWorkers bounds concurrent requests when fetching many resources.
+const fetchWorkers = 8
+
+var (
+	listsMu sync.Mutex
+	lists   = map[string][]ListEntry{}
+)
+
+// PokemonNames returns the name of every Pokemon. The list is fetched once,
+// page by page, and remembered for the life of the process.
+func PokemonNames() ([]string, error) {
+	return entryNames(pokemonEntries())
+}
+
+// LocationAreaNames returns the name of every location area, fetched once
+// like PokemonNames.
+func LocationAreaNames() ([]string, error) {
+	return entryNames(rememberedList("location-area/"))
+}
+
+func pokemonEntries() ([]ListEntry, error) {
+	return rememberedList("pokemon/")
+}
+
+func entryNames(entries []ListEntry, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	return names, nil
+}
+
+// rememberedList walks a whole list endpoint once per process.
+func rememberedList(resource string) ([]ListEntry, error) {
+	url := fmt.Sprintf("%s%s?offset=0&limit=%d", baseURL, resource, listPageSize)
+	listsMu.Lock()
+	defer listsMu.Unlock()
+	if cached, ok := lists[url]; ok {
+		return cached, nil
+	}
+	all, err := listAll(url)
+	if err != nil {
+		return nil, err
+	}
+	lists[url] = all
+	return all, nil
+}
+
+// listAll follows next links from url until the last page.
+func listAll(url string) ([]ListEntry, error) {
+	var all []ListEntry
+	for next := &url; next != nil; {
+		page, err := cachedFetch[PaginatedResponse[ListEntry]](*next)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Results...)
+		next = page.Next
+	}
+	return all, nil
+}
+
+// fetchAll calls fetch for every name using a few workers and returns the
+// results in the same order. If any fetch fails, the first error in name
+// order is returned.
+func fetchAll[T any](names []string, fetch func(name string) (T, error)) ([]T, error) {
+	results := make([]T, len(names))
+	errs := make([]error, len(names))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(fetchWorkers, len(names)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results[j], errs[j] = fetch(names[j])
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func GetPokemonSpecies(species string) (PokemonSpecies, error) {
+	url := baseURL + "pokemon-species/" + species
+	return cachedFetch[PokemonSpecies](url)
+}
+
+// GetPokemonEncounters returns every location area where a Pokemon can be
+// found in the wild. The list is empty for Pokemon that are only gifted,
+// traded or evolved.
+func GetPokemonEncounters(name string) ([]LocationAreaEncounter, error) {
+	pokemon, err := GetPokemon(name)
+	if err != nil {
+		return nil, err
+	}
+	return cachedFetch[[]LocationAreaEncounter](pokemon.LocationAreaEncounters)
+}
+
+func GetType(name string) (TypeDetails, error) {
+	url := baseURL + "type/" + name
+	return cachedFetch[TypeDetails](url)
+}
+
+func GetMove(name string) (MoveDetails, error) {
+	url := baseURL + "move/" + name
+	return cachedFetch[MoveDetails](url)
+}
+
+// LanguageNames returns the code of every language the PokeAPI has names in,
+// such as "en", "ja-Hrkt" or "fr".
+func LanguageNames() ([]string, error) {
+	return entryNames(rememberedList("language/"))
+}
+
+// Localize picks the name in lang from names, falling back to English and
+// then to fallback when the resource has no name in either.
+func Localize(names []Name, lang, fallback string) string {
+	english := ""
+	for _, name := range names {
+		if name.Name == "" {
+			continue
+		}
+		if name.Language.Name == lang {
+			return name.Name
+		}
+		if name.Language.Name == "en" {
+			english = name.Name
+		}
+	}
+	if english != "" {
+		return english
+	}
+	return fallback
+}
+
 func cachedFetch[Response any](url string) (Response, error) {
 	var result Response
 	var zero Response
@@ -57,34 +273,117 @@ func cachedFetch[Response any](url string) (Response, error) {
 		}
 		// in case of error, we'll just fetch the data again
 	}
+	// A stale saved response is still better than none when the API can't
+	// be reached.
+	var stale []byte
+	if persistent != nil {
+		saved, fetchedAt, ok := persistent.Get(url)
+		if ok && json.Unmarshal(saved, &result) == nil {
+			if time.Since(fetchedAt) < maxAge(url) {
+				cache.Add(url, saved)
+				return result, nil
+			}
+			stale = saved
+		}
+		result = zero
+	}
+	useStale := func() (Response, error) {
+		json.Unmarshal(stale, &result)
+		cache.Add(url, stale)
+		return result, nil
+	}
 
-	res, err := http.Get(url)
+	res, err := get(url, nil)
 	if err != nil {
-		fmt.Println("Error:", err)
-		return zero, fmt.Errorf("error: %v", err)
+		if stale != nil {
+			return useStale()
+		}
+		return zero, err
 	}
 	defer res.Body.Close()
 
 	resBody, err := io.ReadAll(res.Body)
+	if res.StatusCode == http.StatusNotFound {
+		return zero, fmt.Errorf("%w: %s", ErrNotFound, url)
+	}
 	if res.StatusCode >= 400 {
-		fmt.Println("Error:", res.Status, string(resBody))
+		if stale != nil {
+			return useStale()
+		}
 		return zero, fmt.Errorf("error: %v %v\n%s", res.StatusCode, res.Status, resBody)
 	}
 
 	if err != nil {
-		fmt.Println("Error:", err)
 		return zero, fmt.Errorf("error: %v", err)
 	}
 
 	err = json.Unmarshal(resBody, &result)
 	if err != nil {
-		fmt.Println("Error:", err)
 		return zero, fmt.Errorf("error: %v", err)
 	}
 	cache.Add(url, resBody)
+	if persistent != nil {
+		persistent.Add(url, resBody)
+	}
 	return result, nil
 }
 
+// NamedResource is a reference to another PokeAPI resource.
+type NamedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Name is a resource's name in one language.
+type Name struct {
+	Name     string        `json:"name"`
+	Language NamedResource `json:"language"`
+}
+
+type PokemonSpecies struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Names     []Name `json:"names"`
+	Varieties []struct {
+		IsDefault bool          `json:"is_default"`
+		Pokemon   NamedResource `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+// DefaultVariety is the name of the species' default Pokemon, which is
+// usually but not always the species name.
+func (s PokemonSpecies) DefaultVariety() string {
+	for _, variety := range s.Varieties {
+		if variety.IsDefault {
+			return variety.Pokemon.Name
+		}
+	}
+	return s.Name
+}
+
+type TypeDetails struct {
+	ID              int             `json:"id"`
+	Name            string          `json:"name"`
+	Names           []Name          `json:"names"`
+	DamageRelations DamageRelations `json:"damage_relations"`
+}
+
+// DamageRelations lists the types a type is strong or weak against.
+type DamageRelations struct {
+	DoubleDamageFrom []NamedResource `json:"double_damage_from"`
+	HalfDamageFrom   []NamedResource `json:"half_damage_from"`
+	NoDamageFrom     []NamedResource `json:"no_damage_from"`
+	DoubleDamageTo   []NamedResource `json:"double_damage_to"`
+	HalfDamageTo     []NamedResource `json:"half_damage_to"`
+	NoDamageTo       []NamedResource `json:"no_damage_to"`
+}
+
+type MoveDetails struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Names []Name `json:"names"`
+}
+
 type LocationDetails struct {
 	ID                   int    `json:"id"`
 	Name                 string `json:"name"`
@@ -106,38 +405,39 @@ type LocationDetails struct {
 		Name string `json:"name"`
 		URL  string `json:"url"`
 	} `json:"location"`
-	Names []struct {
-		Name     string `json:"name"`
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-	} `json:"names"`
+	Names             []Name `json:"names"`
 	PokemonEncounters []struct {
 		Pokemon struct {
 			Name string `json:"name"`
 			URL  string `json:"url"`
 		} `json:"pokemon"`
-		VersionDetails []struct {
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
-			MaxChance        int `json:"max_chance"`
-			EncounterDetails []struct {
-				MinLevel        int   `json:"min_level"`
-				MaxLevel        int   `json:"max_level"`
-				ConditionValues []any `json:"condition_values"`
-				Chance          int   `json:"chance"`
-				Method          struct {
-					Name string `json:"name"`
-					URL  string `json:"url"`
-				} `json:"method"`
-			} `json:"encounter_details"`
-		} `json:"version_details"`
+		VersionDetails []VersionEncounterDetail `json:"version_details"`
 	} `json:"pokemon_encounters"`
 }
 
+// LocationAreaEncounter is one area a Pokemon can be found in.
+type LocationAreaEncounter struct {
+	LocationArea   NamedResource            `json:"location_area"`
+	VersionDetails []VersionEncounterDetail `json:"version_details"`
+}
+
+// VersionEncounterDetail lists how a Pokemon can be encountered in one game.
+type VersionEncounterDetail struct {
+	Version          NamedResource     `json:"version"`
+	MaxChance        int               `json:"max_chance"`
+	EncounterDetails []EncounterDetail `json:"encounter_details"`
+}
+
+// EncounterDetail is one encounter slot. A Pokemon often has several slots
+// for the same method, whose chances add up.
+type EncounterDetail struct {
+	MinLevel        int             `json:"min_level"`
+	MaxLevel        int             `json:"max_level"`
+	ConditionValues []NamedResource `json:"condition_values"`
+	Chance          int             `json:"chance"`
+	Method          NamedResource   `json:"method"`
+}
+
 type PokemonDetails struct {
 	ID             int    `json:"id"`
 	Name           string `json:"name"`
@@ -409,3 +709,26 @@ type PokemonDetails struct {
 		} `json:"types"`
 	} `json:"past_types"`
 }
+
+// Clone returns a copy of p that shares no slices with it, so changing one
+// doesn't change the other.
+func (p PokemonDetails) Clone() PokemonDetails {
+	p.Abilities = slices.Clone(p.Abilities)
+	p.Forms = slices.Clone(p.Forms)
+	p.GameIndices = slices.Clone(p.GameIndices)
+	p.HeldItems = slices.Clone(p.HeldItems)
+	for i := range p.HeldItems {
+		p.HeldItems[i].VersionDetails = slices.Clone(p.HeldItems[i].VersionDetails)
+	}
+	p.Moves = slices.Clone(p.Moves)
+	for i := range p.Moves {
+		p.Moves[i].VersionGroupDetails = slices.Clone(p.Moves[i].VersionGroupDetails)
+	}
+	p.Stats = slices.Clone(p.Stats)
+	p.Types = slices.Clone(p.Types)
+	p.PastTypes = slices.Clone(p.PastTypes)
+	for i := range p.PastTypes {
+		p.PastTypes[i].Types = slices.Clone(p.PastTypes[i].Types)
+	}
+	return p
+}
@@ -0,0 +1,328 @@
+package pokeapi_test
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shamsup/pokedexcli/internal/pokeapitest"
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+func TestGetLocationsFollowsPagination(t *testing.T) {
+	server := pokeapitest.NewServer(t)
+
+	first, err := pokeapi.GetLocations("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Results) != 20 || first.Results[0].Name != "canalave-city-area" {
+		t.Fatalf("unexpected first page %+v", first.Results)
+	}
+	if first.Previous != nil || first.Next == nil {
+		t.Fatalf("expected only a next link on the first page, got %v %v", first.Previous, first.Next)
+	}
+	if !strings.HasPrefix(*first.Next, server.URL) {
+		t.Errorf("expected next link to point at the test server, got %s", *first.Next)
+	}
+
+	second, err := pokeapi.GetLocations(*first.Next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Results[0].Name != "mt-coronet-1f-route-216" || second.Previous == nil {
+		t.Errorf("unexpected second page %+v", second)
+	}
+}
+
+func TestGetLocationsPage(t *testing.T) {
+	pokeapitest.NewServer(t)
+
+	page, err := pokeapi.GetLocationsPage(20, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Results[0].Name != "mt-coronet-1f-route-216" {
+		t.Errorf("expected the second page, got %+v", page.Results)
+	}
+}
+
+func TestGetPokemonUsesCache(t *testing.T) {
+	server := pokeapitest.NewServer(t)
+
+	for i := 0; i < 3; i++ {
+		pokemon, err := pokeapi.GetPokemon("charmander")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pokemon.ID != 4 || pokemon.Types[0].Type.Name != "fire" {
+			t.Fatalf("unexpected pokemon %+v", pokemon)
+		}
+	}
+	if requests := server.Requests(); len(requests) != 1 {
+		t.Errorf("expected one request, got %v", requests)
+	}
+}
+
+// agedCache is a pokeapi.ResponseCache whose responses were fetched at
+// set times.
+type agedCache struct {
+	mu        sync.Mutex
+	responses map[string]agedResponse
+}
+
+type agedResponse struct {
+	body      string
+	fetchedAt time.Time
+}
+
+func (c *agedCache) Get(url string) ([]byte, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.responses[url]
+	return []byte(r.body), r.fetchedAt, ok
+}
+
+func (c *agedCache) Add(url string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses[url] = agedResponse{string(body), time.Now()}
+}
+
+func TestPersistentCacheExpires(t *testing.T) {
+	server := pokeapitest.NewServer(t)
+	api := server.URL + "/api/v2/"
+	cache := &agedCache{responses: map[string]agedResponse{
+		api + "pokemon/pikachu":    {`{"id": 1025}`, time.Now().Add(-24 * time.Hour)},
+		api + "pokemon/charmander": {`{"id": 1025}`, time.Now().Add(-60 * 24 * time.Hour)},
+		api + "location-area/":     {`{"count": 1}`, time.Now().Add(-48 * time.Hour)},
+		api + "pokemon/bulbasaur":  {`{"id": 1025}`, time.Now().Add(-60 * 24 * time.Hour)},
+	}}
+	previous := pokeapi.SetPersistentCache(cache)
+	t.Cleanup(func() { pokeapi.SetPersistentCache(previous) })
+
+	if pokemon, err := pokeapi.GetPokemon("pikachu"); err != nil || pokemon.ID != 1025 {
+		t.Errorf("expected the saved pikachu, got %d, %v", pokemon.ID, err)
+	}
+	if pokemon, err := pokeapi.GetPokemon("charmander"); err != nil || pokemon.ID != 4 {
+		t.Errorf("expected an old charmander to be fetched again, got %d, %v", pokemon.ID, err)
+	}
+	if locations, err := pokeapi.GetLocations(""); err != nil || locations.Count == 1 {
+		t.Errorf("expected a day-old list to be fetched again, got %d, %v", locations.Count, err)
+	}
+	if requests := server.Requests(); len(requests) != 2 {
+		t.Errorf("expected two requests, got %v", requests)
+	}
+	if _, fetchedAt, _ := cache.Get(api + "pokemon/charmander"); time.Since(fetchedAt) > time.Minute {
+		t.Errorf("expected the new charmander to be saved")
+	}
+
+	server.Close()
+	if pokemon, err := pokeapi.GetPokemon("bulbasaur"); err != nil || pokemon.ID != 1025 {
+		t.Errorf("expected the old bulbasaur when offline, got %d, %v", pokemon.ID, err)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	pokeapitest.NewServer(t)
+
+	_, err := pokeapi.GetLocationDetails("nowhere")
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestNameIndexes(t *testing.T) {
+	server := pokeapitest.NewServer(t)
+
+	pokemon, err := pokeapi.PokemonNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pokemon) != 24 || pokemon[0] != "bulbasaur" || pokemon[len(pokemon)-1] != "lumineon" {
+		t.Errorf("expected names from both pages, got %v", pokemon)
+	}
+	areas, err := pokeapi.LocationAreaNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(areas) != 40 {
+		t.Errorf("expected 40 areas, got %d", len(areas))
+	}
+
+	before := len(server.Requests())
+	if _, err := pokeapi.PokemonNames(); err != nil {
+		t.Fatal(err)
+	}
+	if after := len(server.Requests()); after != before {
+		t.Errorf("expected the index to be remembered, saw %d new requests", after-before)
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	pokeapitest.NewServer(t)
+
+	electric, err := pokeapi.GetType("electric")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		names    []pokeapi.Name
+		lang     string
+		expected string
+	}{
+		{electric.Names, "ja-Hrkt", "でんき"},
+		{electric.Names, "fr", "Électrik"},
+		{electric.Names, "ko", "Electric"},
+		{nil, "fr", "electric"},
+		{[]pokeapi.Name{{Name: "", Language: pokeapi.NamedResource{Name: "fr"}}}, "fr", "electric"},
+	}
+	for _, c := range cases {
+		if got := pokeapi.Localize(c.names, c.lang, "electric"); got != c.expected {
+			t.Errorf("Localize(%s): expected %q, got %q", c.lang, c.expected, got)
+		}
+	}
+
+	area, err := pokeapi.GetLocationDetails("canalave-city-area")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pokeapi.Localize(area.Names, "de", area.Name); got != "Fleetburg" {
+		t.Errorf("expected the German area name, got %q", got)
+	}
+	move, err := pokeapi.GetMove("thunderbolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pokeapi.Localize(move.Names, "fr", move.Name); got != "Tonnerre" {
+		t.Errorf("expected the French move name, got %q", got)
+	}
+}
+
+func TestRegionAreas(t *testing.T) {
+	pokeapitest.NewServer(t)
+
+	areas, err := pokeapi.RegionAreas("kanto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(areas, " ") != "pallet-town-area kanto-route-1-area viridian-city-area" {
+		t.Errorf("expected kanto's areas in location order, got %v", areas)
+	}
+
+	if _, err := pokeapi.RegionAreas("orre"); !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown region, got %v", err)
+	}
+
+	location, err := pokeapi.GetLocation("canalave-city")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.Region.Name != "sinnoh" || len(location.Areas) != 1 {
+		t.Errorf("unexpected location %+v", location)
+	}
+}
+
+func TestGetPokemonEncounters(t *testing.T) {
+	pokeapitest.NewServer(t)
+
+	encounters, err := pokeapi.GetPokemonEncounters("pikachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encounters) != 2 || encounters[0].LocationArea.Name != "trophy-garden-area" {
+		t.Fatalf("unexpected encounters %+v", encounters)
+	}
+	details := encounters[0].VersionDetails[2]
+	if details.Version.Name != "platinum" || details.EncounterDetails[0].Chance != 10 || details.EncounterDetails[0].Method.Name != "walk" {
+		t.Errorf("unexpected platinum encounter %+v", details)
+	}
+
+	if encounters, err := pokeapi.GetPokemonEncounters("charmander"); err != nil || len(encounters) != 0 {
+		t.Errorf("expected no wild encounters for charmander, got %v, %v", encounters, err)
+	}
+}
+
+func TestAssetCache(t *testing.T) {
+	const etag = `"v1"`
+	var requests, conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			// The first request fails once to exercise retrying.
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/cry.ogg" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == etag {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "audio/ogg")
+		w.Header().Set("ETag", etag)
+		w.Write([]byte("OggS"))
+	}))
+	defer server.Close()
+	url := server.URL + "/cry.ogg"
+	dir := t.TempDir()
+
+	asset, err := pokeapi.NewAssetCache(dir, 0).Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(asset.Data) != "OggS" || asset.ContentType != "audio/ogg" || requests != 2 {
+		t.Errorf("unexpected asset %+v after %d requests", asset, requests)
+	}
+
+	// A fresh saved copy is used without asking the server.
+	if _, err := pokeapi.NewAssetCache(dir, time.Hour).Get(url); err != nil || requests != 2 {
+		t.Errorf("expected the saved asset to be used, got %v after %d requests", err, requests)
+	}
+	// A stale one is revalidated.
+	cache := pokeapi.NewAssetCache(dir, 0)
+	asset, err = cache.Get(url)
+	if err != nil || string(asset.Data) != "OggS" || conditional != 1 {
+		t.Errorf("expected a conditional request, got %+v, %v after %d", asset, err, conditional)
+	}
+	// Within a session it's kept in memory.
+	if _, err := cache.Get(url); err != nil || conditional != 1 {
+		t.Errorf("expected the asset to be kept in memory, got %v", err)
+	}
+
+	if _, err := cache.Get(server.URL + "/missing.ogg"); !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	// Saved assets still work once the server is gone.
+	server.Close()
+	if asset, err := pokeapi.NewAssetCache(dir, 0).Get(url); err != nil || string(asset.Data) != "OggS" {
+		t.Errorf("expected the saved asset offline, got %+v, %v", asset, err)
+	}
+}
+
+func TestDefensiveMatchups(t *testing.T) {
+	pokeapitest.NewServer(t)
+
+	// Psychic and fairy: their weaknesses and resistances multiply, and
+	// cancel out for bug and dark.
+	got, err := pokeapi.DefensiveMatchups([]string{"psychic", "fairy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]float64{
+		"fighting": 0.25, "psychic": 0.5, "dragon": 0,
+		"ghost": 2, "poison": 2, "steel": 2,
+	}
+	if !maps.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
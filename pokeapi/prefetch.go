@@ -0,0 +1,67 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrNoPersistentCache is returned by Prefetch when there is nowhere to
+// keep what it fetches.
+var ErrNoPersistentCache = errors.New("no persistent cache")
+
+// HasPersistentCache reports whether SetPersistentCache has been called.
+func HasPersistentCache() bool {
+	return persistent != nil
+}
+
+// DetailURLs returns the URL of every resource in a list endpoint, such as
+// "pokemon/", written the way the Get functions request them so that
+// prefetched responses are found in the cache.
+func DetailURLs(resource string) ([]string, error) {
+	entries, err := rememberedList(resource)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		urls = append(urls, baseURL+resource+entry.Name)
+	}
+	return urls, nil
+}
+
+// Prefetch saves the response at url in the persistent cache unless a
+// fresh copy is already there, and reports whether it had to be fetched.
+// Responses skip the in-memory cache so prefetching thousands of them
+// doesn't fill it.
+func Prefetch(url string) (bool, error) {
+	if persistent == nil {
+		return false, ErrNoPersistentCache
+	}
+	if _, fetchedAt, ok := persistent.Get(url); ok && time.Since(fetchedAt) < maxAge(url) {
+		return false, nil
+	}
+	res, err := get(url, nil)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return false, fmt.Errorf("%w: %s", ErrNotFound, url)
+	}
+	if res.StatusCode >= 400 {
+		return false, fmt.Errorf("error: %v %v", res.StatusCode, res.Status)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, fmt.Errorf("error: %v", err)
+	}
+	if !json.Valid(body) {
+		return false, fmt.Errorf("error: invalid JSON from %s", url)
+	}
+	persistent.Add(url, body)
+	return true, nil
+}
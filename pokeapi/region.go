@@ -0,0 +1,68 @@
+package pokeapi
+
+// Region is a region such as kanto, made up of locations.
+type Region struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	Names          []Name          `json:"names"`
+	Locations      []NamedResource `json:"locations"`
+	MainGeneration NamedResource   `json:"main_generation"`
+	VersionGroups  []NamedResource `json:"version_groups"`
+}
+
+// Location is a place such as pallet-town. Pokemon are found in its areas,
+// which are what LocationDetails describes.
+type Location struct {
+	ID     int             `json:"id"`
+	Name   string          `json:"name"`
+	Names  []Name          `json:"names"`
+	Region NamedResource   `json:"region"`
+	Areas  []NamedResource `json:"areas"`
+}
+
+func GetRegion(name string) (Region, error) {
+	url := baseURL + "region/" + name
+	return cachedFetch[Region](url)
+}
+
+func GetLocation(name string) (Location, error) {
+	url := baseURL + "location/" + name
+	return cachedFetch[Location](url)
+}
+
+// RegionNames returns the name of every region, fetched once like
+// PokemonNames.
+func RegionNames() ([]string, error) {
+	return entryNames(rememberedList("region/"))
+}
+
+// LocationNames returns the name of every location, fetched once like
+// PokemonNames.
+func LocationNames() ([]string, error) {
+	return entryNames(rememberedList("location/"))
+}
+
+// RegionAreas returns every location area in a region, in the order the
+// region lists its locations. It fetches each location, so the first call
+// for a region is slow; the responses are cached.
+func RegionAreas(region string) ([]string, error) {
+	r, err := GetRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(r.Locations))
+	for i, location := range r.Locations {
+		names[i] = location.Name
+	}
+	locations, err := fetchAll(names, GetLocation)
+	if err != nil {
+		return nil, err
+	}
+	var areas []string
+	for _, location := range locations {
+		for _, area := range location.Areas {
+			areas = append(areas, area.Name)
+		}
+	}
+	return areas, nil
+}
@@ -0,0 +1,213 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// localizedNamesKey is where the index of localized names is kept in the
+// persistent cache. Bump the version if its format changes.
+const localizedNamesKey = "pokedexcli:localized-names/v1"
+
+var slugReplacer = strings.NewReplacer(
+	"♀", "-f",
+	"♂", "-m",
+	"é", "e",
+	"É", "e",
+	".", "",
+	"'", "",
+	"’", "",
+	":", "",
+	" ", "-",
+	"_", "-",
+)
+
+// Slugify converts a display name such as "Mr. Mime" or "Farfetch'd" into
+// the form the PokeAPI uses in URLs ("mr-mime", "farfetchd").
+func Slugify(name string) string {
+	slug := slugReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+	return strings.Trim(slug, "-")
+}
+
+// ResolvePokemon turns what a user typed into a Pokemon's API name. It
+// accepts national dex numbers ("25"), API names ("pikachu"), display names
+// ("Mr. Mime", "Farfetch'd"), species with several forms ("deoxys") and
+// localized names from any language the API knows ("ピカチュウ"). The error
+// wraps ErrNotFound when nothing matches.
+func ResolvePokemon(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("%w: empty pokemon name", ErrNotFound)
+	}
+
+	if id, err := strconv.Atoi(input); err == nil {
+		return resolvePokemonID(id)
+	}
+
+	slug := Slugify(input)
+	if names, err := PokemonNames(); err == nil {
+		for _, name := range names {
+			if name == slug {
+				return name, nil
+			}
+		}
+	}
+
+	// Species like deoxys are only available as forms such as deoxys-normal.
+	species, err := GetPokemonSpecies(slug)
+	if err == nil {
+		return species.DefaultVariety(), nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return "", err
+	}
+
+	localized, err := localizedPokemonNames()
+	if err != nil {
+		return "", err
+	}
+	if name, ok := localized[normalizeLocalized(input)]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("%w: pokemon %q", ErrNotFound, input)
+}
+
+func resolvePokemonID(id int) (string, error) {
+	if names, err := pokemonEntries(); err == nil {
+		for _, entry := range names {
+			if resourceID(entry.Url) == id {
+				return entry.Name, nil
+			}
+		}
+	}
+	pokemon, err := GetPokemon(strconv.Itoa(id))
+	if err != nil {
+		return "", err
+	}
+	return pokemon.Name, nil
+}
+
+// resourceID extracts the numeric ID from a resource URL such as
+// https://pokeapi.co/api/v2/pokemon/25/.
+func resourceID(url string) int {
+	id, err := strconv.Atoi(path.Base(strings.TrimSuffix(url, "/")))
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func normalizeLocalized(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// localizedIndex is an index of localized names being built, or built;
+// done is closed once names is set.
+type localizedIndex struct {
+	done  chan struct{}
+	names map[string]string
+	err   error
+}
+
+var (
+	localizedMu      sync.Mutex
+	localizedIndexes = map[string]*localizedIndex{}
+)
+
+// localizedPokemonNames maps every species' name in every language to its
+// default Pokemon. Building it fetches each species once, so the first
+// lookup by a localized name or a typo is slow; a complete index is saved
+// in the persistent cache so that happens once rather than every session.
+// Lookups while it's being built wait for it rather than fetching again.
+func localizedPokemonNames() (map[string]string, error) {
+	url := baseURL + "pokemon-species/"
+	localizedMu.Lock()
+	if index, ok := localizedIndexes[url]; ok {
+		localizedMu.Unlock()
+		<-index.done
+		return index.names, index.err
+	}
+	index := &localizedIndex{done: make(chan struct{})}
+	localizedIndexes[url] = index
+	localizedMu.Unlock()
+	defer close(index.done)
+
+	if names, ok := savedLocalizedNames(); ok {
+		index.names = names
+		return names, nil
+	}
+	// Species that fail are skipped, and the next lookup tries them again.
+	names, skipped, err := indexLocalizedNames()
+	index.names, index.err = names, err
+	if err != nil || skipped > 0 {
+		localizedMu.Lock()
+		delete(localizedIndexes, url)
+		localizedMu.Unlock()
+		return names, err
+	}
+	if persistent != nil {
+		if data, err := json.Marshal(names); err == nil {
+			persistent.Add(localizedNamesKey, data)
+		}
+	}
+	return names, nil
+}
+
+// savedLocalizedNames returns the index of localized names saved in the
+// persistent cache, unless it's old enough to be missing new species.
+func savedLocalizedNames() (map[string]string, bool) {
+	if persistent == nil {
+		return nil, false
+	}
+	saved, fetchedAt, ok := persistent.Get(localizedNamesKey)
+	if !ok || time.Since(fetchedAt) >= responseMaxAge {
+		return nil, false
+	}
+	var names map[string]string
+	if json.Unmarshal(saved, &names) != nil || len(names) == 0 {
+		return nil, false
+	}
+	return names, true
+}
+
+// indexLocalizedNames indexes the names of every species, and counts those
+// it skipped because they couldn't be fetched.
+func indexLocalizedNames() (map[string]string, int, error) {
+	names, err := entryNames(rememberedList("pokemon-species/"))
+	if err != nil {
+		return nil, 0, err
+	}
+	type result struct {
+		species PokemonSpecies
+		ok      bool
+	}
+	results, err := fetchAll(names, func(name string) (result, error) {
+		species, err := GetPokemonSpecies(name)
+		return result{species, err == nil}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	index := map[string]string{}
+	skipped := 0
+	for _, r := range results {
+		if !r.ok {
+			skipped++
+			continue
+		}
+		for _, name := range r.species.Names {
+			index[normalizeLocalized(name.Name)] = r.species.DefaultVariety()
+		}
+	}
+	return index, skipped, nil
+}
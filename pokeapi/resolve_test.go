@@ -0,0 +1,84 @@
+package pokeapi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shamsup/pokedexcli/internal/pokeapitest"
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Pikachu":    "pikachu",
+		"Mr. Mime":   "mr-mime",
+		"Farfetch'd": "farfetchd",
+		"Farfetch’d": "farfetchd",
+		"Nidoran♀":   "nidoran-f",
+		"Type: Null": "type-null",
+		"Flabébé":    "flabebe",
+		" mime  jr ": "mime-jr",
+		"tapu_koko":  "tapu-koko",
+	}
+	for input, expected := range cases {
+		if actual := pokeapi.Slugify(input); actual != expected {
+			t.Errorf("Slugify(%q): expected %q, got %q", input, expected, actual)
+		}
+	}
+}
+
+func TestResolvePokemon(t *testing.T) {
+	pokeapitest.NewServer(t)
+
+	cases := map[string]string{
+		"pikachu":    "pikachu",
+		"25":         "pikachu",
+		"Pikachu":    "pikachu",
+		"Mr. Mime":   "mr-mime",
+		"farfetch'd": "farfetchd",
+		"deoxys":     "deoxys-normal",
+		"ピカチュウ":      "pikachu",
+		"Glumanda":   "charmander",
+		"m. mime":    "mr-mime",
+		"皮卡丘":        "pikachu",
+	}
+	for input, expected := range cases {
+		actual, err := pokeapi.ResolvePokemon(input)
+		if err != nil {
+			t.Errorf("ResolvePokemon(%q): unexpected error %v", input, err)
+			continue
+		}
+		if actual != expected {
+			t.Errorf("ResolvePokemon(%q): expected %q, got %q", input, expected, actual)
+		}
+	}
+
+	for _, input := range []string{"", "missingno", "9999"} {
+		if _, err := pokeapi.ResolvePokemon(input); !errors.Is(err, pokeapi.ErrNotFound) {
+			t.Errorf("ResolvePokemon(%q): expected ErrNotFound, got %v", input, err)
+		}
+	}
+}
+
+func TestLocalizedNamesAreSaved(t *testing.T) {
+	cache := &agedCache{responses: map[string]agedResponse{}}
+	previous := pokeapi.SetPersistentCache(cache)
+	t.Cleanup(func() { pokeapi.SetPersistentCache(previous) })
+
+	pokeapitest.NewServer(t)
+	if actual, err := pokeapi.ResolvePokemon("Glumanda"); err != nil || actual != "charmander" {
+		t.Fatalf("expected charmander, got %q, %v", actual, err)
+	}
+
+	// A later session finds the names without fetching every species.
+	server := pokeapitest.NewServer(t)
+	if actual, err := pokeapi.ResolvePokemon("ピカチュウ"); err != nil || actual != "pikachu" {
+		t.Errorf("expected pikachu, got %q, %v", actual, err)
+	}
+	for _, request := range server.Requests() {
+		if strings.HasSuffix(request, "/pokemon-species/pikachu") {
+			t.Errorf("expected the saved names to be used, got %s", request)
+		}
+	}
+}
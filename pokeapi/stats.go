@@ -0,0 +1,123 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+)
+
+// statsIndexKey is where the stats index is kept in the persistent cache.
+// Bump the version if StatsIndex changes.
+const statsIndexKey = "pokedexcli:stats-index/v1"
+
+// firstFormID is where the PokeAPI numbers alternate forms, such as megas,
+// from. Pokemon below it are the default form of each species.
+const firstFormID = 10001
+
+// StatTotal is the key of base stat totals in a StatsIndex.
+const StatTotal = "total"
+
+// StatsIndex holds the base stats of every species, to rank a Pokemon's
+// stats against them.
+type StatsIndex struct {
+	// Count is how many species the index was built from.
+	Count int `json:"count"`
+	// Stats holds each stat's values in ascending order, keyed by stat
+	// name, with base stat totals under StatTotal.
+	Stats map[string][]int `json:"stats"`
+}
+
+// Percentile returns the percentage of species with a lower value for
+// stat, or false if the index doesn't have the stat.
+func (s StatsIndex) Percentile(stat string, value int) (int, bool) {
+	values := s.Stats[stat]
+	if len(values) == 0 {
+		return 0, false
+	}
+	below, _ := slices.BinarySearch(values, value)
+	return below * 100 / len(values), true
+}
+
+var (
+	statsIndexMu sync.Mutex
+	statsIndex   *StatsIndex
+)
+
+// LoadStatsIndex returns the stats index last built by BuildStatsIndex, or
+// false if there isn't one. Ranking against only the few species cached
+// along the way would be misleading, so it's never built here.
+func LoadStatsIndex() (StatsIndex, bool) {
+	statsIndexMu.Lock()
+	defer statsIndexMu.Unlock()
+	if statsIndex != nil {
+		return *statsIndex, statsIndex.Count > 0
+	}
+	if persistent == nil {
+		return StatsIndex{}, false
+	}
+	saved, _, ok := persistent.Get(statsIndexKey)
+	if !ok {
+		return StatsIndex{}, false
+	}
+	var index StatsIndex
+	if json.Unmarshal(saved, &index) != nil || index.Count == 0 {
+		return StatsIndex{}, false
+	}
+	statsIndex = &index
+	return index, true
+}
+
+// BuildStatsIndex ranks the base stats of every species that is already
+// cached, without fetching anything else, and saves the index for
+// LoadStatsIndex. Prefetch Pokemon first to include them all.
+func BuildStatsIndex() (StatsIndex, error) {
+	entries, err := pokemonEntries()
+	if err != nil {
+		return StatsIndex{}, err
+	}
+	index := StatsIndex{Stats: map[string][]int{}}
+	for _, entry := range entries {
+		if resourceID(entry.Url) >= firstFormID {
+			continue
+		}
+		pokemon, ok := cachedResponse[PokemonDetails](baseURL + "pokemon/" + entry.Name)
+		if !ok {
+			continue
+		}
+		index.Count++
+		total := 0
+		for _, stat := range pokemon.Stats {
+			index.Stats[stat.Stat.Name] = append(index.Stats[stat.Stat.Name], stat.BaseStat)
+			total += stat.BaseStat
+		}
+		index.Stats[StatTotal] = append(index.Stats[StatTotal], total)
+	}
+	for _, values := range index.Stats {
+		slices.Sort(values)
+	}
+
+	statsIndexMu.Lock()
+	defer statsIndexMu.Unlock()
+	statsIndex = &index
+	if persistent != nil && index.Count > 0 {
+		if data, err := json.Marshal(index); err == nil {
+			persistent.Add(statsIndexKey, data)
+		}
+	}
+	return index, nil
+}
+
+// cachedResponse decodes a response from the in-memory or persistent
+// cache, without fetching it.
+func cachedResponse[Response any](url string) (Response, bool) {
+	var result Response
+	body, ok := cache.Get(url)
+	if !ok && persistent != nil {
+		body, _, ok = persistent.Get(url)
+	}
+	if !ok || json.Unmarshal(body, &result) != nil {
+		var zero Response
+		return zero, false
+	}
+	return result, true
+}
@@ -0,0 +1,171 @@
+package pokeapi
+
+import "strings"
+
+// Version is a single game, such as platinum.
+type Version struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name"`
+	Names        []Name        `json:"names"`
+	VersionGroup NamedResource `json:"version_group"`
+}
+
+// VersionGroup is a set of games released together, such as
+// diamond-pearl. Moves are learned per version group.
+type VersionGroup struct {
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Order      int             `json:"order"`
+	Generation NamedResource   `json:"generation"`
+	Versions   []NamedResource `json:"versions"`
+	Regions    []NamedResource `json:"regions"`
+}
+
+func GetVersion(name string) (Version, error) {
+	url := baseURL + "version/" + name
+	return cachedFetch[Version](url)
+}
+
+func GetVersionGroup(name string) (VersionGroup, error) {
+	url := baseURL + "version-group/" + name
+	return cachedFetch[VersionGroup](url)
+}
+
+// VersionNames returns the name of every game, fetched once like
+// PokemonNames.
+func VersionNames() ([]string, error) {
+	return entryNames(rememberedList("version/"))
+}
+
+var romanNumerals = map[string]int{
+	"i": 1, "ii": 2, "iii": 3, "iv": 4, "v": 5,
+	"vi": 6, "vii": 7, "viii": 8, "ix": 9, "x": 10,
+}
+
+// GenerationNumber turns a generation's name, such as "generation-iv",
+// into its number. It returns 0 for names it doesn't recognize.
+func GenerationNumber(name string) int {
+	return romanNumerals[strings.TrimPrefix(name, "generation-")]
+}
+
+// lastGameIndexGeneration is the last generation the PokeAPI records game
+// indices for.
+const lastGameIndexGeneration = 5
+
+// InVersion reports whether p appears in a game. Game indices stop after
+// generation V, so Pokemon are assumed to appear in later games.
+func InVersion(p PokemonDetails, version string) (bool, error) {
+	for _, index := range p.GameIndices {
+		if index.Version.Name == version {
+			return true, nil
+		}
+	}
+	v, err := GetVersion(version)
+	if err != nil {
+		return false, err
+	}
+	group, err := GetVersionGroup(v.VersionGroup.Name)
+	if err != nil {
+		return false, err
+	}
+	return GenerationNumber(group.Generation.Name) > lastGameIndexGeneration, nil
+}
+
+// TypeNamesIn returns p's types as they were in a generation. Types changed
+// for a few Pokemon, such as Mr. Mime gaining Fairy in generation VI. A
+// generation of 0 means the current types.
+func (p PokemonDetails) TypeNamesIn(generation int) []string {
+	if generation > 0 {
+		best := 0
+		var names []string
+		for _, past := range p.PastTypes {
+			// Past types apply up to and including their generation.
+			n := GenerationNumber(past.Generation.Name)
+			if n < generation || (best != 0 && n >= best) {
+				continue
+			}
+			best = n
+			names = names[:0]
+			for _, t := range past.Types {
+				names = append(names, t.Type.Name)
+			}
+		}
+		if best != 0 {
+			return names
+		}
+	}
+	var names []string
+	for _, t := range p.Types {
+		names = append(names, t.Type.Name)
+	}
+	return names
+}
+
+// SpriteSet holds one game's sprites for a Pokemon. Older games lack some
+// of them, which are left empty.
+type SpriteSet struct {
+	Front      string
+	Back       string
+	FrontShiny string
+	BackShiny  string
+}
+
+// SpritesFor returns the sprites from a game, named by version or version
+// group, falling back to the current default sprites when the PokeAPI has
+// none for it.
+func (p PokemonDetails) SpritesFor(version, versionGroup string) SpriteSet {
+	v := p.Sprites.Versions
+	var set SpriteSet
+	switch {
+	case versionGroup == "red-blue":
+		s := v.GenerationI.RedBlue
+		set = SpriteSet{Front: s.FrontDefault, Back: s.BackDefault}
+	case versionGroup == "yellow":
+		s := v.GenerationI.Yellow
+		set = SpriteSet{Front: s.FrontDefault, Back: s.BackDefault}
+	case version == "gold":
+		s := v.GenerationIi.Gold
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	case version == "silver":
+		s := v.GenerationIi.Silver
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	case versionGroup == "crystal":
+		s := v.GenerationIi.Crystal
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	case versionGroup == "ruby-sapphire":
+		s := v.GenerationIii.RubySapphire
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	case versionGroup == "emerald":
+		s := v.GenerationIii.Emerald
+		set = SpriteSet{Front: s.FrontDefault, FrontShiny: s.FrontShiny}
+	case versionGroup == "firered-leafgreen":
+		s := v.GenerationIii.FireredLeafgreen
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	case versionGroup == "diamond-pearl":
+		s := v.GenerationIv.DiamondPearl
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	case versionGroup == "platinum":
+		s := v.GenerationIv.Platinum
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	case versionGroup == "heartgold-soulsilver":
+		s := v.GenerationIv.HeartgoldSoulsilver
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	case versionGroup == "black-white" || versionGroup == "black-2-white-2":
+		s := v.GenerationV.BlackWhite
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	case versionGroup == "x-y":
+		s := v.GenerationVi.XY
+		set = SpriteSet{Front: s.FrontDefault, FrontShiny: s.FrontShiny}
+	case versionGroup == "omega-ruby-alpha-sapphire":
+		s := v.GenerationVi.OmegarubyAlphasapphire
+		set = SpriteSet{Front: s.FrontDefault, FrontShiny: s.FrontShiny}
+	case versionGroup == "ultra-sun-ultra-moon":
+		s := v.GenerationVii.UltraSunUltraMoon
+		set = SpriteSet{Front: s.FrontDefault, FrontShiny: s.FrontShiny}
+	}
+	if set.Front == "" {
+		s := p.Sprites
+		set = SpriteSet{s.FrontDefault, s.BackDefault, s.FrontShiny, s.BackShiny}
+	}
+	return set
+}
@@ -0,0 +1,62 @@
+package pokeapi_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shamsup/pokedexcli/internal/pokeapitest"
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+func TestGenerationNumber(t *testing.T) {
+	cases := map[string]int{"generation-i": 1, "generation-iv": 4, "generation-viii": 8, "generation-ix": 9, "gen-4": 0}
+	for name, expected := range cases {
+		if got := pokeapi.GenerationNumber(name); got != expected {
+			t.Errorf("GenerationNumber(%q): expected %d, got %d", name, expected, got)
+		}
+	}
+}
+
+func TestVersionHelpers(t *testing.T) {
+	pokeapitest.NewServer(t)
+
+	mime, err := pokeapi.GetPokemon("mr-mime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		generation int
+		expected   []string
+	}{
+		{0, []string{"psychic", "fairy"}},
+		{1, []string{"psychic"}},
+		{5, []string{"psychic"}},
+		{6, []string{"psychic", "fairy"}},
+	}
+	for _, c := range cases {
+		if got := mime.TypeNamesIn(c.generation); !slices.Equal(got, c.expected) {
+			t.Errorf("TypeNamesIn(%d): expected %v, got %v", c.generation, c.expected, got)
+		}
+	}
+
+	for version, expected := range map[string]bool{"red": true, "platinum": false, "x": true} {
+		got, err := pokeapi.InVersion(mime, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expected {
+			t.Errorf("InVersion(mr-mime, %s): expected %v, got %v", version, expected, got)
+		}
+	}
+
+	pikachu, err := pokeapi.GetPokemon("pikachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pikachu.SpritesFor("red", "red-blue"); got.Front != pikachu.Sprites.Versions.GenerationI.RedBlue.FrontDefault || got.FrontShiny != "" {
+		t.Errorf("expected red-blue sprites without a shiny, got %+v", got)
+	}
+	if got := pikachu.SpritesFor("sword", "sword-shield"); got.Front != pikachu.Sprites.FrontDefault || got.BackShiny != pikachu.Sprites.BackShiny {
+		t.Errorf("expected the default sprites for a game without its own, got %+v", got)
+	}
+}
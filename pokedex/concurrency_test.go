@@ -0,0 +1,82 @@
+package pokedex
+
+import (
+	"sync"
+	"testing"
+)
+
+// These tests are most useful under the race detector: go test -race ./...
+
+func TestConcurrentCatchInspectList(t *testing.T) {
+	const workers = 16
+	const iterations = 200
+	p := NewPokedex(WithAPIClient(&mockAPIClient{}), WithCatchPolicy(guessTrue))
+	names := []string{"charmander", "bulbasaur"}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				name := names[(w+i)%len(names)]
+				switch i % 4 {
+				case 0:
+					if _, _, err := p.CatchPokemon(name); err != nil {
+						t.Errorf("catch %s: %v", name, err)
+					}
+				case 1:
+					p.InspectPokemon(name)
+				case 2:
+					if _, err := p.ListCaughtPokemon(); err != nil {
+						t.Errorf("list: %v", err)
+					}
+				case 3:
+					p.SeenPokemon(name)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	caught, err := p.ListCaughtPokemon()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(caught) != len(names) {
+		t.Errorf("expected %d caught pokemon, got %v", len(names), caught)
+	}
+}
+
+func TestListIsSnapshot(t *testing.T) {
+	p := NewPokedex(WithAPIClient(&mockAPIClient{}), WithCatchPolicy(guessTrue))
+	if _, _, err := p.CatchPokemon("charmander"); err != nil {
+		t.Fatal(err)
+	}
+	before, err := p.ListCaughtPokemon()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := p.CatchPokemon("bulbasaur"); err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 1 {
+		t.Errorf("expected earlier snapshot to be unchanged, got %v", before)
+	}
+	before[0] = "mutated"
+	if !p.SeenPokemon("charmander") {
+		t.Errorf("expected mutating a snapshot not to affect the pokedex")
+	}
+
+	p = newQueryPokedex(t)
+	entries, err := p.Query(Query{Sort: SortByID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries[0].Pokemon.Stats[0].BaseStat = 999
+	entries[0].Pokemon.Types[0].Type.Name = "mutated"
+	entries, _ = p.Query(Query{Sort: SortByID})
+	if entries[0].Pokemon.Stats[0].BaseStat == 999 || entries[0].Pokemon.Types[0].Type.Name == "mutated" {
+		t.Errorf("expected mutating an entry's stats and types not to affect the pokedex, got %+v", entries[0].Pokemon)
+	}
+}
@@ -0,0 +1,194 @@
+package pokedex
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormatVersion is written into JSON and CSV exports so readers can
+// tell which fields to expect. Bump it when Record changes incompatibly.
+const ExportFormatVersion = 1
+
+// statOrder is the order base stats appear in exports, as in the games.
+var statOrder = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// Record is a caught Pokemon flattened for export.
+type Record struct {
+	ID        int            `json:"id"`
+	Name      string         `json:"name"`
+	Types     []string       `json:"types"`
+	Stats     map[string]int `json:"stats"`
+	BST       int            `json:"bst"`
+	Abilities []string       `json:"abilities"`
+	// Height is in metres and Weight in kilograms.
+	Height   float64   `json:"height"`
+	Weight   float64   `json:"weight"`
+	CaughtAt time.Time `json:"caught_at"`
+	Attempts int       `json:"attempts"`
+}
+
+// NewRecord flattens a Pokedex entry. Hidden abilities are marked with
+// "(hidden)".
+func NewRecord(entry Entry) Record {
+	pokemon := entry.Pokemon
+	r := Record{
+		ID:        pokemon.ID,
+		Name:      pokemon.Name,
+		Types:     []string{},
+		Stats:     map[string]int{},
+		BST:       BaseStatTotal(pokemon),
+		Abilities: []string{},
+		Height:    float64(pokemon.Height) / 10,
+		Weight:    float64(pokemon.Weight) / 10,
+		CaughtAt:  entry.CaughtAt,
+		Attempts:  entry.Attempts,
+	}
+	for _, t := range pokemon.Types {
+		r.Types = append(r.Types, t.Type.Name)
+	}
+	for _, stat := range pokemon.Stats {
+		r.Stats[stat.Stat.Name] = stat.BaseStat
+	}
+	for _, ability := range pokemon.Abilities {
+		name := ability.Ability.Name
+		if ability.IsHidden {
+			name += " (hidden)"
+		}
+		r.Abilities = append(r.Abilities, name)
+	}
+	return r
+}
+
+// Exporter writes caught Pokemon in one file format.
+type Exporter interface {
+	Export(w io.Writer, records []Record) error
+}
+
+// ExporterFunc adapts a function to the Exporter interface.
+type ExporterFunc func(w io.Writer, records []Record) error
+
+func (f ExporterFunc) Export(w io.Writer, records []Record) error {
+	return f(w, records)
+}
+
+// Exporters maps format names, which are also file extensions, to their
+// Exporter. Add to it to support another format.
+var Exporters = map[string]Exporter{
+	"csv":  ExporterFunc(ExportCSV),
+	"json": ExporterFunc(ExportJSON),
+	"md":   ExporterFunc(ExportMarkdown),
+}
+
+// ExportFormats returns the names of every format in Exporters, sorted.
+func ExportFormats() []string {
+	formats := make([]string, 0, len(Exporters))
+	for format := range Exporters {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// Export writes the caught Pokemon matching q to w in format. Without a
+// sort, Pokemon are exported in Pokedex order.
+func (p *Pokedex) Export(w io.Writer, format string, q Query) (int, error) {
+	exporter, ok := Exporters[format]
+	if !ok {
+		return 0, fmt.Errorf("unknown export format %q, expected one of %s", format, strings.Join(ExportFormats(), ", "))
+	}
+	if q.Sort == "" {
+		q.Sort = SortByID
+	}
+	entries, err := p.Query(q)
+	if err != nil {
+		return 0, err
+	}
+	records := make([]Record, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, NewRecord(entry))
+	}
+	return len(records), exporter.Export(w, records)
+}
+
+// columns returns the header and rows shared by the tabular formats.
+func columns(records []Record) (header []string, rows [][]string) {
+	header = []string{"id", "name", "types"}
+	header = append(header, statOrder...)
+	header = append(header, "bst", "abilities", "height_m", "weight_kg", "caught_at", "attempts")
+	for _, r := range records {
+		row := []string{strconv.Itoa(r.ID), r.Name, strings.Join(r.Types, "/")}
+		for _, stat := range statOrder {
+			row = append(row, strconv.Itoa(r.Stats[stat]))
+		}
+		row = append(row,
+			strconv.Itoa(r.BST),
+			strings.Join(r.Abilities, "/"),
+			strconv.FormatFloat(r.Height, 'f', -1, 64),
+			strconv.FormatFloat(r.Weight, 'f', -1, 64),
+			r.CaughtAt.Format(time.RFC3339),
+			strconv.Itoa(r.Attempts),
+		)
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// ExportCSV writes one row per Pokemon under a header row. Types and
+// abilities are joined with "/", and a last format_version column repeats
+// ExportFormatVersion so it survives sorting and filtering rows.
+func ExportCSV(w io.Writer, records []Record) error {
+	header, rows := columns(records)
+	header = append(header, "format_version")
+	version := strconv.Itoa(ExportFormatVersion)
+	for i := range rows {
+		rows[i] = append(rows[i], version)
+	}
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	cw.WriteAll(rows)
+	return cw.Error()
+}
+
+// jsonExport is the top level of a JSON export.
+type jsonExport struct {
+	FormatVersion int      `json:"format_version"`
+	Pokemon       []Record `json:"pokemon"`
+}
+
+// ExportJSON writes the records under a format_version.
+func ExportJSON(w io.Writer, records []Record) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonExport{FormatVersion: ExportFormatVersion, Pokemon: records})
+}
+
+// ExportMarkdown writes a table for wiki pages.
+func ExportMarkdown(w io.Writer, records []Record) error {
+	header, rows := columns(records)
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			// Pipes would end the cell early.
+			b.WriteString(" " + strings.ReplaceAll(cell, "|", `\|`) + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeRow(separator)
+	for _, row := range rows {
+		writeRow(row)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
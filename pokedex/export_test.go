@@ -0,0 +1,84 @@
+package pokedex
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	p := newQueryPokedex(t)
+	cases := []struct {
+		format   string
+		contains []string
+	}{
+		{
+			format: "csv",
+			contains: []string{
+				"id,name,types,hp,attack,defense,special-attack,special-defense,speed,bst,abilities,height_m,weight_kg,caught_at,attempts,format_version\n",
+				"1,bulbasaur,grass/poison,45,49,0,0,0,45,139,,0,0,2024-08-01T12:03:00Z,1,1\n4,charmander,",
+				"4,charmander,fire,39,52,0,0,0,65,156,blaze/solar-power (hidden),0.6,8.5,2024-08-01T12:04:00Z,1,1\n",
+			},
+		},
+		{
+			format: "md",
+			contains: []string{
+				"| id | name | types | hp |",
+				"| --- | --- |",
+				"| 4 | charmander | fire | 39 | 52 | 0 | 0 | 0 | 65 | 156 | blaze/solar-power (hidden) | 0.6 | 8.5 | 2024-08-01T12:04:00Z | 1 |\n",
+			},
+		},
+		{
+			format:   "json",
+			contains: []string{`"format_version": 1`, `"abilities": [`, `"solar-power (hidden)"`},
+		},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		count, err := p.Export(&out, c.format, Query{})
+		if err != nil || count != 4 {
+			t.Fatalf("%s: expected 4 Pokemon, got %d, %v", c.format, count, err)
+		}
+		for _, want := range c.contains {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s: expected %q in\n%s", c.format, want, out.String())
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	if _, err := p.Export(&out, "json", Query{Types: []string{"flying"}}); err != nil {
+		t.Fatal(err)
+	}
+	var exported jsonExport
+	if err := json.Unmarshal(out.Bytes(), &exported); err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Pokemon) != 2 || exported.Pokemon[0].Name != "charizard" || exported.Pokemon[1].Stats["speed"] != 56 {
+		t.Errorf("unexpected export %+v", exported)
+	}
+
+	if _, err := p.Export(&out, "xlsx", Query{}); err == nil || !strings.Contains(err.Error(), "csv, json, md") {
+		t.Errorf("expected an unknown format error, got %v", err)
+	}
+}
+
+func TestCustomExporter(t *testing.T) {
+	Exporters["names"] = ExporterFunc(func(w io.Writer, records []Record) error {
+		for _, r := range records {
+			io.WriteString(w, r.Name+"\n")
+		}
+		return nil
+	})
+	defer delete(Exporters, "names")
+
+	var out bytes.Buffer
+	if _, err := newQueryPokedex(t).Export(&out, "names", Query{Sort: SortByName}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "bulbasaur\ncharizard\ncharmander\npidgey\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,238 @@
+package pokedex
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Importer reads caught Pokemon written by an Exporter.
+type Importer interface {
+	Import(r io.Reader) ([]Record, error)
+}
+
+// ImporterFunc adapts a function to the Importer interface.
+type ImporterFunc func(r io.Reader) ([]Record, error)
+
+func (f ImporterFunc) Import(r io.Reader) ([]Record, error) {
+	return f(r)
+}
+
+// Importers maps format names to their Importer. Markdown is meant for
+// people, so it can't be imported.
+var Importers = map[string]Importer{
+	"csv":  ImporterFunc(ImportCSV),
+	"json": ImporterFunc(ImportJSON),
+}
+
+// ImportFormats returns the names of every format in Importers, sorted.
+func ImportFormats() []string {
+	formats := make([]string, 0, len(Importers))
+	for format := range Importers {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// ErrFormatVersion is returned for exports written by an incompatible
+// version of pokedexcli.
+var ErrFormatVersion = errors.New("unsupported format version")
+
+// ErrNoName is reported for imported records without a name.
+var ErrNoName = errors.New("no name")
+
+// ImportJSON reads a JSON export, refusing any format version it doesn't
+// understand.
+func ImportJSON(r io.Reader) ([]Record, error) {
+	var export jsonExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("reading JSON: %w", err)
+	}
+	if export.FormatVersion != ExportFormatVersion {
+		return nil, fmt.Errorf("%w %d, expected %d", ErrFormatVersion, export.FormatVersion, ExportFormatVersion)
+	}
+	return export.Pokemon, nil
+}
+
+// ImportCSV reads a CSV export. Only the name column is required, so a
+// hand-written list of names works too; the id, caught_at and attempts
+// columns are used when present and the rest are looked up again. Rows with
+// a format_version other than ExportFormatVersion are refused.
+func ImportCSV(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	column := map[string]int{}
+	for i, name := range header {
+		column[strings.TrimSpace(name)] = i
+	}
+	if _, ok := column["name"]; !ok {
+		return nil, errors.New("not a Pokedex export: the CSV has no name column")
+	}
+	field := func(row []string, name string) string {
+		if i, ok := column[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var records []Record
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		if version := field(row, "format_version"); version != "" && version != strconv.Itoa(ExportFormatVersion) {
+			return nil, fmt.Errorf("line %d: %w %s, expected %d", line, ErrFormatVersion, version, ExportFormatVersion)
+		}
+		record := Record{Name: field(row, "name")}
+		if record.Name == "" {
+			return nil, fmt.Errorf("line %d: %w", line, ErrNoName)
+		}
+		if id := field(row, "id"); id != "" {
+			if record.ID, err = strconv.Atoi(id); err != nil {
+				return nil, fmt.Errorf("line %d: invalid id %q", line, id)
+			}
+		}
+		if caughtAt := field(row, "caught_at"); caughtAt != "" {
+			if record.CaughtAt, err = time.Parse(time.RFC3339, caughtAt); err != nil {
+				return nil, fmt.Errorf("line %d: invalid caught_at %q", line, caughtAt)
+			}
+		}
+		if attempts := field(row, "attempts"); attempts != "" {
+			if record.Attempts, err = strconv.Atoi(attempts); err != nil {
+				return nil, fmt.Errorf("line %d: invalid attempts %q", line, attempts)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// MergeStrategy decides what happens when an imported Pokemon has already
+// been caught.
+type MergeStrategy string
+
+const (
+	// MergeKeep keeps the existing entry.
+	MergeKeep MergeStrategy = "keep"
+	// MergeOverwrite replaces it with the imported one.
+	MergeOverwrite MergeStrategy = "overwrite"
+	// MergeUnion combines them: the earliest catch and the attempts of both.
+	MergeUnion MergeStrategy = "union"
+)
+
+// MergeStrategies lists every supported MergeStrategy.
+var MergeStrategies = []MergeStrategy{MergeKeep, MergeOverwrite, MergeUnion}
+
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	for _, strategy := range MergeStrategies {
+		if string(strategy) == s {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown strategy %q", s)
+}
+
+// ImportReport says what an import did with each record.
+type ImportReport struct {
+	// Added were not caught before.
+	Added []string
+	// Conflicts were already caught with different details, and were
+	// resolved by the strategy.
+	Conflicts []string
+	// Unchanged were already caught with the same details.
+	Unchanged []string
+	// Invalid couldn't be imported, keyed by the name in the file, or by
+	// "(record n)" for the nth record if it has no name.
+	Invalid map[string]error
+}
+
+// Import validates records against the API and merges them into the
+// Pokedex, resolving Pokemon caught in both with strategy. Records without
+// a catch time are caught now.
+func (p *Pokedex) Import(records []Record, strategy MergeStrategy) (ImportReport, error) {
+	report := ImportReport{Invalid: map[string]error{}}
+	if !slices.Contains(MergeStrategies, strategy) {
+		return report, fmt.Errorf("unknown strategy %q", strategy)
+	}
+
+	// Look everything up before taking the lock, as CatchPokemon does.
+	var valid []Entry
+	for i, record := range records {
+		// A blank name would fetch the list of every Pokemon instead.
+		if strings.TrimSpace(record.Name) == "" {
+			report.Invalid[fmt.Sprintf("(record %d)", i+1)] = ErrNoName
+			continue
+		}
+		pokemon, err := p.api.GetPokemon(record.Name)
+		if err != nil {
+			report.Invalid[record.Name] = err
+			continue
+		}
+		if record.ID != 0 && record.ID != pokemon.ID {
+			report.Invalid[record.Name] = fmt.Errorf("id %d doesn't match #%d", record.ID, pokemon.ID)
+			continue
+		}
+		valid = append(valid, Entry{
+			Pokemon:   pokemon,
+			Collected: true,
+			CaughtAt:  record.CaughtAt,
+			Attempts:  record.Attempts,
+		})
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, imported := range valid {
+		name := imported.Pokemon.Name
+		existing, ok, err := p.store.Get(name)
+		if err != nil {
+			return report, err
+		}
+		merged := imported
+		switch {
+		case !ok || !existing.Collected:
+			// Earlier misses still count towards catching it.
+			merged.Attempts += existing.Attempts
+			if merged.CaughtAt.IsZero() {
+				merged.CaughtAt = p.clock()
+			}
+			report.Added = append(report.Added, name)
+		case (imported.CaughtAt.IsZero() || existing.CaughtAt.Equal(imported.CaughtAt)) && existing.Attempts == imported.Attempts:
+			report.Unchanged = append(report.Unchanged, name)
+			continue
+		default:
+			// Without a catch time of its own, it keeps the existing one.
+			if imported.CaughtAt.IsZero() {
+				merged.CaughtAt = existing.CaughtAt
+			}
+			report.Conflicts = append(report.Conflicts, name)
+			switch strategy {
+			case MergeKeep:
+				continue
+			case MergeUnion:
+				merged.Attempts += existing.Attempts
+				if imported.CaughtAt.IsZero() || (!existing.CaughtAt.IsZero() && existing.CaughtAt.Before(imported.CaughtAt)) {
+					merged.CaughtAt = existing.CaughtAt
+				}
+			}
+		}
+		if err := p.store.Put(name, merged); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,117 @@
+package pokedex
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportRoundTrip(t *testing.T) {
+	for _, format := range []string{"csv", "json"} {
+		var out bytes.Buffer
+		if _, err := newQueryPokedex(t).Export(&out, format, Query{}); err != nil {
+			t.Fatal(err)
+		}
+		records, err := Importers[format].Import(&out)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		p := NewPokedex(WithAPIClient(fixtureAPIClient(queryFixtures)))
+		report, err := p.Import(records, MergeKeep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Added) != 4 || len(report.Conflicts) != 0 {
+			t.Errorf("%s: expected 4 Pokemon added, got %+v", format, report)
+		}
+		entries, _ := p.Query(Query{Sort: SortByID})
+		caughtAt := time.Date(2024, time.August, 1, 12, 3, 0, 0, time.UTC)
+		if entries[0].Pokemon.Name != "bulbasaur" || !entries[0].CaughtAt.Equal(caughtAt) || entries[0].Attempts != 1 {
+			t.Errorf("%s: expected bulbasaur's catch details to be imported, got %+v", format, entries[0])
+		}
+	}
+}
+
+func TestImportStrategies(t *testing.T) {
+	early := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Name: "charmander", CaughtAt: early, Attempts: 3},
+		{Name: "pidgey", CaughtAt: time.Date(2024, time.August, 1, 12, 1, 0, 0, time.UTC), Attempts: 1},
+		{Name: "squirtle"},
+		{Name: "charizard", ID: 5},
+		{Name: " "},
+	}
+	cases := []struct {
+		strategy         MergeStrategy
+		expectedCaughtAt time.Time
+		expectedAttempts int
+	}{
+		{MergeKeep, time.Date(2024, time.August, 1, 12, 4, 0, 0, time.UTC), 1},
+		{MergeOverwrite, early, 3},
+		{MergeUnion, early, 4},
+	}
+	for _, c := range cases {
+		p := newQueryPokedex(t)
+		report, err := p.Import(records, c.strategy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(report.Conflicts, []string{"charmander"}) || !slices.Equal(report.Unchanged, []string{"pidgey"}) || len(report.Added) != 0 {
+			t.Errorf("%s: unexpected report %+v", c.strategy, report)
+		}
+		if len(report.Invalid) != 3 || report.Invalid["squirtle"] == nil || !strings.Contains(report.Invalid["charizard"].Error(), "doesn't match #6") {
+			t.Errorf("%s: expected squirtle and charizard to be invalid, got %v", c.strategy, report.Invalid)
+		}
+		if !errors.Is(report.Invalid["(record 5)"], ErrNoName) {
+			t.Errorf("%s: expected the record without a name to be invalid, got %v", c.strategy, report.Invalid)
+		}
+		entries, _ := p.Query(Query{Types: []string{"fire"}, Sort: SortByID})
+		if !entries[0].CaughtAt.Equal(c.expectedCaughtAt) || entries[0].Attempts != c.expectedAttempts {
+			t.Errorf("%s: expected charmander caught at %v in %d attempts, got %+v", c.strategy, c.expectedCaughtAt, c.expectedAttempts, entries[0])
+		}
+	}
+
+	if _, err := newQueryPokedex(t).Import(records, "merge"); err == nil {
+		t.Errorf("expected an error for an unknown strategy")
+	}
+}
+
+func TestImportWithoutCaughtAt(t *testing.T) {
+	now := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)
+	p := NewPokedex(WithAPIClient(fixtureAPIClient(queryFixtures)), WithClock(func() time.Time { return now }))
+	records := []Record{{Name: "charmander", Attempts: 1}}
+	if report, err := p.Import(records, MergeKeep); err != nil || len(report.Added) != 1 {
+		t.Fatalf("expected charmander to be added, got %+v, %v", report, err)
+	}
+	if entries, _ := p.Query(Query{}); !entries[0].CaughtAt.Equal(now) {
+		t.Errorf("expected charmander to be caught at the import time, got %v", entries[0].CaughtAt)
+	}
+	if report, _ := p.Import(records, MergeOverwrite); len(report.Unchanged) != 1 {
+		t.Errorf("expected importing it again to change nothing, got %+v", report)
+	}
+}
+
+func TestImportRefusesOtherVersions(t *testing.T) {
+	_, err := ImportJSON(strings.NewReader(`{"format_version": 2, "pokemon": []}`))
+	if !errors.Is(err, ErrFormatVersion) {
+		t.Errorf("expected ErrFormatVersion, got %v", err)
+	}
+	if _, err := ImportJSON(strings.NewReader(`{"pokemon": []}`)); !errors.Is(err, ErrFormatVersion) {
+		t.Errorf("expected a missing version to be refused, got %v", err)
+	}
+	if _, err := ImportCSV(strings.NewReader("name,format_version\nbulbasaur,2\n")); !errors.Is(err, ErrFormatVersion) {
+		t.Errorf("expected ErrFormatVersion for a CSV, got %v", err)
+	}
+	if _, err := ImportCSV(strings.NewReader("id,species\n1,bulbasaur\n")); err == nil {
+		t.Errorf("expected a CSV without names to be refused")
+	}
+	if _, err := ImportCSV(strings.NewReader("name,attempts\nbulbasaur,many\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected the bad line to be reported, got %v", err)
+	}
+	if _, err := ImportCSV(strings.NewReader("name,attempts\nbulbasaur,1\n ,2\n")); !errors.Is(err, ErrNoName) || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("expected the line without a name to be refused, got %v", err)
+	}
+}
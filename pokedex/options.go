@@ -0,0 +1,44 @@
+package pokedex
+
+import "time"
+
+// Option configures a Pokedex created by NewPokedex.
+type Option func(*Pokedex)
+
+// WithAPIClient sets the client used to look up Pokemon that have not been
+// encountered yet.
+func WithAPIClient(api APIClient) Option {
+	return func(p *Pokedex) {
+		if api != nil {
+			p.api = api
+		}
+	}
+}
+
+// WithCatchPolicy sets the policy that decides whether a thrown Pokeball
+// catches its target.
+func WithCatchPolicy(policy CatchPolicy) Option {
+	return func(p *Pokedex) {
+		if policy != nil {
+			p.policy = policy
+		}
+	}
+}
+
+// WithStore sets where Pokedex entries are kept.
+func WithStore(store Store) Option {
+	return func(p *Pokedex) {
+		if store != nil {
+			p.store = store
+		}
+	}
+}
+
+// WithClock sets the function used to timestamp catches.
+func WithClock(clock func() time.Time) Option {
+	return func(p *Pokedex) {
+		if clock != nil {
+			p.clock = clock
+		}
+	}
+}
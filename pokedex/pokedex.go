@@ -0,0 +1,150 @@
+// Package pokedex tracks the Pokemon a trainer has encountered and caught.
+package pokedex
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+// Entry is a single Pokemon recorded in the Pokedex.
+type Entry struct {
+	Pokemon   pokeapi.PokemonDetails
+	Collected bool
+	CaughtAt  time.Time
+	// Attempts counts the Pokeballs thrown at the Pokemon until it was
+	// caught, including the successful one.
+	Attempts int
+}
+
+func (e Entry) clone() Entry {
+	e.Pokemon = e.Pokemon.Clone()
+	return e
+}
+
+// Pokedex is safe for concurrent use by multiple goroutines.
+type Pokedex struct {
+	mu     sync.RWMutex
+	api    APIClient
+	policy CatchPolicy
+	store  Store
+	clock  func() time.Time
+}
+
+type APIClient interface {
+	GetPokemon(name string) (pokeapi.PokemonDetails, error)
+}
+
+type DefaultAPIClient struct{}
+
+func (DefaultAPIClient) GetPokemon(name string) (pokeapi.PokemonDetails, error) {
+	return pokeapi.GetPokemon(name)
+}
+
+// NewPokedex creates an empty Pokedex. Without options it fetches Pokemon
+// from the PokeAPI, uses DefaultCatchPolicy and keeps its entries in memory.
+func NewPokedex(opts ...Option) *Pokedex {
+	p := &Pokedex{
+		api:    DefaultAPIClient{},
+		policy: DefaultCatchPolicy{},
+		store:  NewMemoryStore(),
+		clock:  time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Pokedex) SeenPokemon(name string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	entry, ok, err := p.store.Get(name)
+	if err != nil || !ok {
+		return false
+	}
+	return entry.Collected
+}
+
+// CatchPokemon throws a Pokeball at the named Pokemon and reports whether it
+// was caught. Throwing again at a caught Pokemon and missing lets it go.
+func (p *Pokedex) CatchPokemon(name string) (pokeapi.PokemonDetails, bool, error) {
+	var zeroPokemon pokeapi.PokemonDetails
+	p.mu.RLock()
+	_, ok, err := p.store.Get(name)
+	p.mu.RUnlock()
+	if err != nil {
+		return zeroPokemon, false, err
+	}
+	// Look the Pokemon up without holding the lock so a slow API call
+	// doesn't block everyone else.
+	var fetched pokeapi.PokemonDetails
+	if !ok {
+		fetched, err = p.api.GetPokemon(name)
+		if err != nil {
+			return zeroPokemon, false, err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Another goroutine may have recorded the Pokemon while we were fetching.
+	entry, ok, err := p.store.Get(name)
+	if err != nil {
+		return zeroPokemon, false, err
+	}
+	if !ok {
+		entry = Entry{Pokemon: fetched}
+	}
+
+	caught := p.policy.Attempt(entry.Pokemon)
+	switch {
+	case !entry.Collected:
+		entry.Attempts++
+		if caught {
+			entry.Collected = true
+			entry.CaughtAt = p.clock()
+		}
+	case !caught:
+		// Throwing at a caught Pokemon risks it breaking free.
+		entry.Collected = false
+		entry.CaughtAt = time.Time{}
+	}
+	if err := p.store.Put(name, entry); err != nil {
+		return zeroPokemon, false, err
+	}
+	return entry.Pokemon, caught, nil
+}
+
+func (p *Pokedex) InspectPokemon(name string) (pokeapi.PokemonDetails, error) {
+	var zeroPokemon pokeapi.PokemonDetails
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	entry, ok, err := p.store.Get(name)
+	if err != nil {
+		return zeroPokemon, err
+	}
+	if ok && entry.Collected {
+		return entry.Pokemon, nil
+	}
+	return zeroPokemon, fmt.Errorf("you have not caught that pokemon")
+}
+
+// ListCaughtPokemon returns a snapshot of the names of every caught Pokemon.
+func (p *Pokedex) ListCaughtPokemon() ([]string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	entries, err := p.store.List()
+	if err != nil {
+		return nil, err
+	}
+	var collected []string
+	for _, entry := range entries {
+		if entry.Collected {
+			collected = append(collected, entry.Pokemon.Name)
+		}
+	}
+	return collected, nil
+}
@@ -0,0 +1,148 @@
+package pokedex
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+type mockAPIClient struct{}
+
+func (m *mockAPIClient) GetPokemon(name string) (pokeapi.PokemonDetails, error) {
+	switch name {
+	case "charmander":
+		return pokeapi.PokemonDetails{
+			Name:           "charmander",
+			BaseExperience: 64,
+		}, nil
+	case "bulbasaur":
+		return pokeapi.PokemonDetails{
+			Name:           "bulbasaur",
+			BaseExperience: 64,
+		}, nil
+	default:
+		return pokeapi.PokemonDetails{}, fmt.Errorf("pokemon not found")
+	}
+}
+
+var guessTrue = CatchPolicyFunc(func(pokeapi.PokemonDetails) bool {
+	return true
+})
+
+var guessFalse = CatchPolicyFunc(func(pokeapi.PokemonDetails) bool {
+	return false
+})
+
+func TestCatchPokemon(t *testing.T) {
+
+	// Test the CatchPokemon method
+	cases := []struct {
+		pokemonName string
+		expected    bool
+		expectedErr error
+		options     []Option
+	}{
+		{
+			pokemonName: "charmander",
+			expected:    true,
+			expectedErr: nil,
+			options: []Option{
+				WithAPIClient(&mockAPIClient{}),
+				WithCatchPolicy(guessTrue),
+			},
+		},
+		{
+			pokemonName: "bulbasaur",
+			expected:    false,
+			expectedErr: nil,
+			options: []Option{
+				WithAPIClient(&mockAPIClient{}),
+				WithCatchPolicy(guessFalse),
+			},
+		},
+		{
+			pokemonName: "pikachu",
+			expected:    false,
+			expectedErr: fmt.Errorf("pokemon not found"),
+			options: []Option{
+				WithAPIClient(&mockAPIClient{}),
+				WithCatchPolicy(guessTrue),
+			},
+		},
+	}
+	for _, c := range cases {
+		p := NewPokedex(c.options...)
+		pokemon, result, err := p.CatchPokemon(c.pokemonName)
+		if err != nil && c.expectedErr == nil {
+			t.Errorf("unexpected error %v, got %v", c.expectedErr, err)
+		}
+		if err == nil && c.expectedErr != nil {
+			t.Errorf("expected error %v, got %v", c.expectedErr, result)
+		}
+		if result != c.expected {
+			t.Errorf("expected %v, got %v", c.expected, result)
+		}
+		if result && pokemon.Name != c.pokemonName {
+			t.Errorf("expected %v, got %v", c.pokemonName, pokemon.Name)
+		}
+	}
+}
+
+func TestMissedThrowReleasesPokemon(t *testing.T) {
+	caughtAt := time.Date(2024, time.August, 1, 12, 0, 0, 0, time.UTC)
+	policy := guessTrue
+	p := NewPokedex(
+		WithAPIClient(&mockAPIClient{}),
+		WithCatchPolicy(CatchPolicyFunc(func(pokemon pokeapi.PokemonDetails) bool {
+			return policy(pokemon)
+		})),
+		WithClock(func() time.Time { return caughtAt }),
+	)
+	if _, caught, err := p.CatchPokemon("charmander"); err != nil || !caught {
+		t.Fatalf("expected charmander to be caught, got %v %v", caught, err)
+	}
+	if _, caught, err := p.CatchPokemon("charmander"); err != nil || !caught {
+		t.Fatalf("expected second throw to hit, got %v %v", caught, err)
+	}
+	if entries, _ := p.Query(Query{}); len(entries) != 1 || entries[0].Attempts != 1 || !entries[0].CaughtAt.Equal(caughtAt) {
+		t.Errorf("expected throws after the catch not to count as attempts, got %+v", entries)
+	}
+
+	policy = guessFalse
+	if _, caught, err := p.CatchPokemon("charmander"); err != nil || caught {
+		t.Fatalf("expected third throw to miss, got %v %v", caught, err)
+	}
+	if p.SeenPokemon("charmander") {
+		t.Errorf("expected charmander to break free")
+	}
+	names, err := p.ListCaughtPokemon()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 0 {
+		t.Errorf("expected no caught Pokemon, got %v", names)
+	}
+}
+
+func TestWithStore(t *testing.T) {
+	caughtAt := time.Date(2024, time.August, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	p := NewPokedex(
+		WithAPIClient(&mockAPIClient{}),
+		WithCatchPolicy(guessTrue),
+		WithStore(store),
+		WithClock(func() time.Time { return caughtAt }),
+	)
+	if _, _, err := p.CatchPokemon("bulbasaur"); err != nil {
+		t.Fatal(err)
+	}
+	entry, ok, err := store.Get("bulbasaur")
+	if err != nil || !ok {
+		t.Fatalf("expected bulbasaur in the store, got %v %v", ok, err)
+	}
+	if !entry.Collected || !entry.CaughtAt.Equal(caughtAt) {
+		t.Errorf("expected collected entry caught at %v, got %+v", caughtAt, entry)
+	}
+}
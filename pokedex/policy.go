@@ -0,0 +1,29 @@
+package pokedex
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+// CatchPolicy decides whether an attempt to catch a Pokemon succeeds.
+type CatchPolicy interface {
+	Attempt(pokemon pokeapi.PokemonDetails) bool
+}
+
+// CatchPolicyFunc adapts an ordinary function to a CatchPolicy.
+type CatchPolicyFunc func(pokemon pokeapi.PokemonDetails) bool
+
+func (f CatchPolicyFunc) Attempt(pokemon pokeapi.PokemonDetails) bool {
+	return f(pokemon)
+}
+
+// DefaultCatchPolicy makes Pokemon with more base experience harder to catch:
+// the odds are roughly 1 in sqrt(baseExperience - 40).
+type DefaultCatchPolicy struct{}
+
+func (DefaultCatchPolicy) Attempt(pokemon pokeapi.PokemonDetails) bool {
+	odds := int64(math.Max(math.Sqrt(math.Max(1.0, float64(pokemon.BaseExperience-40))), 1.0))
+	return (rand.Int63n(odds) + 1) == 1
+}
@@ -0,0 +1,165 @@
+package pokedex
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+// SortKey orders the results of a Query.
+type SortKey string
+
+const (
+	SortByName     SortKey = "name"
+	SortByID       SortKey = "id"
+	SortByCaughtAt SortKey = "caught-at"
+	SortByBST      SortKey = "bst"
+)
+
+// SortKeys lists every supported SortKey.
+var SortKeys = []SortKey{SortByName, SortByID, SortByCaughtAt, SortByBST}
+
+func ParseSortKey(s string) (SortKey, error) {
+	for _, key := range SortKeys {
+		if string(key) == s {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sort %q", s)
+}
+
+// ParseMinStats reads minimum base stats written as "stat=value", e.g.
+// "attack=80", into a map for Query.MinStats. It returns nil for no pairs.
+func ParseMinStats(pairs []string) (map[string]int, error) {
+	var minStats map[string]int
+	for _, pair := range pairs {
+		stat, minimum, ok := strings.Cut(pair, "=")
+		if !ok || stat == "" {
+			return nil, fmt.Errorf("expected stat=value, got %q", pair)
+		}
+		n, err := strconv.Atoi(minimum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %q", stat, minimum)
+		}
+		if minStats == nil {
+			minStats = map[string]int{}
+		}
+		minStats[stat] = n
+	}
+	return minStats, nil
+}
+
+// Query selects caught Pokemon from the Pokedex.
+type Query struct {
+	// Sort orders the results. Name order is used when empty.
+	Sort SortKey
+	// Types keeps only Pokemon that have every one of these types.
+	Types []string
+	// MinStats keeps only Pokemon whose base stats are at least these values,
+	// keyed by stat name such as "attack" or "special-defense".
+	MinStats map[string]int
+	// Offset skips this many results after sorting.
+	Offset int
+	// Limit caps the number of results. Zero means no limit.
+	Limit int
+}
+
+// Query returns a snapshot of the caught Pokemon that match q. Stores that
+// implement Querier run it themselves.
+func (p *Pokedex) Query(q Query) ([]Entry, error) {
+	if querier, ok := p.store.(Querier); ok {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		return querier.Query(q)
+	}
+
+	p.mu.RLock()
+	entries, err := p.store.List()
+	p.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []Entry
+	for _, entry := range entries {
+		if entry.Collected && q.matches(entry.Pokemon) {
+			matched = append(matched, entry)
+		}
+	}
+
+	sortEntries(matched, q.Sort)
+
+	if q.Offset > 0 {
+		matched = matched[min(q.Offset, len(matched)):]
+	}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+	return matched, nil
+}
+
+func (q Query) matches(pokemon pokeapi.PokemonDetails) bool {
+	for _, want := range q.Types {
+		if !HasType(pokemon, want) {
+			return false
+		}
+	}
+	for name, minimum := range q.MinStats {
+		value, ok := BaseStat(pokemon, name)
+		if !ok || value < minimum {
+			return false
+		}
+	}
+	return true
+}
+
+func sortEntries(entries []Entry, key SortKey) {
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		var c int
+		switch key {
+		case SortByID:
+			c = a.Pokemon.ID - b.Pokemon.ID
+		case SortByCaughtAt:
+			c = a.CaughtAt.Compare(b.CaughtAt)
+		case SortByBST:
+			// Strongest first.
+			c = BaseStatTotal(b.Pokemon) - BaseStatTotal(a.Pokemon)
+		}
+		if c != 0 {
+			return c
+		}
+		return strings.Compare(a.Pokemon.Name, b.Pokemon.Name)
+	})
+}
+
+// HasType reports whether the Pokemon has the named type.
+func HasType(pokemon pokeapi.PokemonDetails, typeName string) bool {
+	for _, t := range pokemon.Types {
+		if t.Type.Name == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// BaseStat returns the Pokemon's base value for the named stat.
+func BaseStat(pokemon pokeapi.PokemonDetails, statName string) (int, bool) {
+	for _, stat := range pokemon.Stats {
+		if stat.Stat.Name == statName {
+			return stat.BaseStat, true
+		}
+	}
+	return 0, false
+}
+
+// BaseStatTotal is the sum of all of the Pokemon's base stats.
+func BaseStatTotal(pokemon pokeapi.PokemonDetails) int {
+	total := 0
+	for _, stat := range pokemon.Stats {
+		total += stat.BaseStat
+	}
+	return total
+}
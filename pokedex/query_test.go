@@ -0,0 +1,144 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+var queryFixtures = map[string]string{
+	"charmander": `{"id": 4, "name": "charmander", "height": 6, "weight": 85, "types": [{"slot": 1, "type": {"name": "fire"}}],
+		"abilities": [{"is_hidden": false, "ability": {"name": "blaze"}}, {"is_hidden": true, "ability": {"name": "solar-power"}}],
+		"stats": [{"base_stat": 39, "stat": {"name": "hp"}}, {"base_stat": 52, "stat": {"name": "attack"}}, {"base_stat": 65, "stat": {"name": "speed"}}]}`,
+	"charizard": `{"id": 6, "name": "charizard", "types": [{"slot": 1, "type": {"name": "fire"}}, {"slot": 2, "type": {"name": "flying"}}],
+		"stats": [{"base_stat": 78, "stat": {"name": "hp"}}, {"base_stat": 84, "stat": {"name": "attack"}}, {"base_stat": 100, "stat": {"name": "speed"}}]}`,
+	"bulbasaur": `{"id": 1, "name": "bulbasaur", "types": [{"slot": 1, "type": {"name": "grass"}}, {"slot": 2, "type": {"name": "poison"}}],
+		"stats": [{"base_stat": 45, "stat": {"name": "hp"}}, {"base_stat": 49, "stat": {"name": "attack"}}, {"base_stat": 45, "stat": {"name": "speed"}}]}`,
+	"pidgey": `{"id": 16, "name": "pidgey", "types": [{"slot": 1, "type": {"name": "normal"}}, {"slot": 2, "type": {"name": "flying"}}],
+		"stats": [{"base_stat": 40, "stat": {"name": "hp"}}, {"base_stat": 45, "stat": {"name": "attack"}}, {"base_stat": 56, "stat": {"name": "speed"}}]}`,
+}
+
+type fixtureAPIClient map[string]string
+
+func (f fixtureAPIClient) GetPokemon(name string) (pokeapi.PokemonDetails, error) {
+	var pokemon pokeapi.PokemonDetails
+	body, ok := f[name]
+	if !ok {
+		return pokemon, fmt.Errorf("pokemon not found")
+	}
+	err := json.Unmarshal([]byte(body), &pokemon)
+	return pokemon, err
+}
+
+func newQueryPokedex(t *testing.T) *Pokedex {
+	t.Helper()
+	now := time.Date(2024, time.August, 1, 12, 0, 0, 0, time.UTC)
+	p := NewPokedex(
+		WithAPIClient(fixtureAPIClient(queryFixtures)),
+		WithCatchPolicy(guessTrue),
+		WithClock(func() time.Time {
+			now = now.Add(time.Minute)
+			return now
+		}),
+	)
+	for _, name := range []string{"pidgey", "charizard", "bulbasaur", "charmander"} {
+		if _, _, err := p.CatchPokemon(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func TestQuery(t *testing.T) {
+	p := newQueryPokedex(t)
+	cases := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{
+			name:     "default sorts by name",
+			query:    Query{},
+			expected: []string{"bulbasaur", "charizard", "charmander", "pidgey"},
+		},
+		{
+			name:     "by id",
+			query:    Query{Sort: SortByID},
+			expected: []string{"bulbasaur", "charmander", "charizard", "pidgey"},
+		},
+		{
+			name:     "by caught-at",
+			query:    Query{Sort: SortByCaughtAt},
+			expected: []string{"pidgey", "charizard", "bulbasaur", "charmander"},
+		},
+		{
+			name:     "by bst",
+			query:    Query{Sort: SortByBST},
+			expected: []string{"charizard", "charmander", "pidgey", "bulbasaur"},
+		},
+		{
+			name:     "by type",
+			query:    Query{Types: []string{"flying"}},
+			expected: []string{"charizard", "pidgey"},
+		},
+		{
+			name:     "by min stat",
+			query:    Query{MinStats: map[string]int{"attack": 50}},
+			expected: []string{"charizard", "charmander"},
+		},
+		{
+			name:     "paged",
+			query:    Query{Sort: SortByID, Offset: 1, Limit: 2},
+			expected: []string{"charmander", "charizard"},
+		},
+		{
+			name:     "offset past the end",
+			query:    Query{Offset: 10},
+			expected: nil,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			entries, err := p.Query(c.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Pokemon.Name)
+			}
+			if !slices.Equal(names, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, names)
+			}
+		})
+	}
+}
+
+func TestParseSortKey(t *testing.T) {
+	if key, err := ParseSortKey("caught-at"); err != nil || key != SortByCaughtAt {
+		t.Errorf("expected caught-at, got %v %v", key, err)
+	}
+	if _, err := ParseSortKey("weight"); err == nil {
+		t.Errorf("expected an error for an unknown sort")
+	}
+}
+
+func TestParseMinStats(t *testing.T) {
+	minStats, err := ParseMinStats([]string{"attack=80", "speed=100"})
+	if err != nil || !maps.Equal(minStats, map[string]int{"attack": 80, "speed": 100}) {
+		t.Errorf("expected attack and speed minimums, got %v %v", minStats, err)
+	}
+	if minStats, err := ParseMinStats(nil); err != nil || minStats != nil {
+		t.Errorf("expected no minimums, got %v %v", minStats, err)
+	}
+	for _, pair := range []string{"attack", "=80", "attack=lots"} {
+		if _, err := ParseMinStats([]string{pair}); err == nil {
+			t.Errorf("expected an error for %q", pair)
+		}
+	}
+}
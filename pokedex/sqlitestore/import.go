@@ -0,0 +1,51 @@
+package sqlitestore
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/shamsup/pokedexcli/pokedex"
+)
+
+// Import is a pokedex.Importer for a saved Pokedex database, such as
+// another trainer's --db file. It reads a copy, so bringing an older
+// schema up to date doesn't change the original, and refuses databases
+// written by a newer version.
+func Import(r io.Reader) ([]pokedex.Record, error) {
+	dir, err := os.MkdirTemp("", "pokedex-import-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "pokedex.db")
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	entries, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("reading saved Pokedex: %w", err)
+	}
+	var records []pokedex.Record
+	for _, entry := range entries {
+		if entry.Collected {
+			records = append(records, pokedex.NewRecord(entry))
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,101 @@
+package sqlitestore
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migrations upgrade the schema one version at a time; the database's
+// user_version is how many have been applied. Only ever append to this
+// list: a migration that has shipped must not change.
+var migrations = []string{
+	// 1: the Pokedex. Pokemon are keyed by the name the Pokedex uses, with
+	// types and base stats split out so queries can filter on them, and the
+	// full PokeAPI response kept in details.
+	`
+	CREATE TABLE pokemon (
+		name            TEXT PRIMARY KEY,
+		id              INTEGER NOT NULL,
+		height          INTEGER NOT NULL,
+		weight          INTEGER NOT NULL,
+		base_experience INTEGER NOT NULL,
+		details         TEXT NOT NULL
+	);
+	CREATE INDEX pokemon_id ON pokemon (id);
+
+	CREATE TABLE types (
+		id   INTEGER PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE
+	);
+	CREATE TABLE pokemon_types (
+		pokemon TEXT NOT NULL REFERENCES pokemon (name) ON DELETE CASCADE,
+		slot    INTEGER NOT NULL,
+		type_id INTEGER NOT NULL REFERENCES types (id),
+		PRIMARY KEY (pokemon, slot)
+	);
+	CREATE INDEX pokemon_types_type ON pokemon_types (type_id);
+
+	CREATE TABLE stats (
+		id   INTEGER PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE
+	);
+	CREATE TABLE pokemon_stats (
+		pokemon   TEXT NOT NULL REFERENCES pokemon (name) ON DELETE CASCADE,
+		stat_id   INTEGER NOT NULL REFERENCES stats (id),
+		base_stat INTEGER NOT NULL,
+		effort    INTEGER NOT NULL,
+		PRIMARY KEY (pokemon, stat_id)
+	);
+	CREATE INDEX pokemon_stats_stat ON pokemon_stats (stat_id, base_stat);
+
+	-- caught_at is in Unix nanoseconds, or NULL if the Pokemon was never
+	-- caught.
+	CREATE TABLE caught (
+		pokemon   TEXT PRIMARY KEY REFERENCES pokemon (name) ON DELETE CASCADE,
+		collected INTEGER NOT NULL,
+		caught_at INTEGER,
+		attempts  INTEGER NOT NULL
+	);
+	CREATE INDEX caught_collected ON caught (collected, caught_at);
+	`,
+
+	// 2: PokeAPI responses, so lookups work offline and survive restarts.
+	`
+	CREATE TABLE api_responses (
+		url        TEXT PRIMARY KEY,
+		body       BLOB NOT NULL,
+		fetched_at INTEGER NOT NULL
+	);
+	`,
+}
+
+// migrate brings the schema up to date, applying each migration in its own
+// transaction. It refuses databases written by a newer pokedexcli.
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this pokedexcli supports (%d)", version, len(migrations))
+	}
+	for ; version < len(migrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrating to schema version %d: %w", version+1, err)
+		}
+		// PRAGMA doesn't take parameters.
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+// Package sqlitestore keeps a Pokedex, and the PokeAPI responses it is
+// built from, in a SQLite database so they last between sessions. Queries
+// run in the database instead of scanning every entry.
+package sqlitestore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+
+	// Registers the pure Go "sqlite" driver.
+	_ "modernc.org/sqlite"
+)
+
+// Store is a pokedex.Store and pokedex.Querier backed by SQLite. It is
+// safe for concurrent use.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens or creates the database at path and brings its schema up to
+// date.
+func Open(path string) (*Store, error) {
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// entryColumns are selected by every query that returns entries, in the
+// order scanEntry reads them.
+const entryColumns = `p.details, c.collected, c.caught_at, c.attempts
+	FROM caught c JOIN pokemon p ON p.name = c.pokemon`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntry(row scanner) (pokedex.Entry, error) {
+	var entry pokedex.Entry
+	var details string
+	var caughtAt sql.NullInt64
+	if err := row.Scan(&details, &entry.Collected, &caughtAt, &entry.Attempts); err != nil {
+		return entry, err
+	}
+	if err := json.Unmarshal([]byte(details), &entry.Pokemon); err != nil {
+		return entry, fmt.Errorf("reading stored Pokemon: %w", err)
+	}
+	if caughtAt.Valid {
+		entry.CaughtAt = time.Unix(0, caughtAt.Int64).UTC()
+	}
+	return entry, nil
+}
+
+func (s *Store) Get(name string) (pokedex.Entry, bool, error) {
+	row := s.db.QueryRow("SELECT "+entryColumns+" WHERE c.pokemon = ?", name)
+	entry, err := scanEntry(row)
+	if err == sql.ErrNoRows {
+		return entry, false, nil
+	}
+	if err != nil {
+		return entry, false, err
+	}
+	return entry, true, nil
+}
+
+// Put saves an entry along with its Pokemon's types and stats.
+func (s *Store) Put(name string, entry pokedex.Entry) error {
+	details, err := json.Marshal(entry.Pokemon)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	pokemon := entry.Pokemon
+	_, err = tx.Exec(`
+		INSERT INTO pokemon (name, id, height, weight, base_experience, details)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			id = excluded.id, height = excluded.height, weight = excluded.weight,
+			base_experience = excluded.base_experience, details = excluded.details`,
+		name, pokemon.ID, pokemon.Height, pokemon.Weight, pokemon.BaseExperience, string(details))
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM pokemon_types WHERE pokemon = ?", name); err != nil {
+		return err
+	}
+	for _, t := range pokemon.Types {
+		if _, err := tx.Exec("INSERT INTO types (name) VALUES (?) ON CONFLICT (name) DO NOTHING", t.Type.Name); err != nil {
+			return err
+		}
+		_, err := tx.Exec("INSERT INTO pokemon_types (pokemon, slot, type_id) SELECT ?, ?, id FROM types WHERE name = ?",
+			name, t.Slot, t.Type.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM pokemon_stats WHERE pokemon = ?", name); err != nil {
+		return err
+	}
+	for _, stat := range pokemon.Stats {
+		if _, err := tx.Exec("INSERT INTO stats (name) VALUES (?) ON CONFLICT (name) DO NOTHING", stat.Stat.Name); err != nil {
+			return err
+		}
+		_, err := tx.Exec("INSERT INTO pokemon_stats (pokemon, stat_id, base_stat, effort) SELECT ?, id, ?, ? FROM stats WHERE name = ?",
+			name, stat.BaseStat, stat.Effort, stat.Stat.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	var caughtAt sql.NullInt64
+	if !entry.CaughtAt.IsZero() {
+		caughtAt = sql.NullInt64{Int64: entry.CaughtAt.UnixNano(), Valid: true}
+	}
+	_, err = tx.Exec(`
+		INSERT INTO caught (pokemon, collected, caught_at, attempts) VALUES (?, ?, ?, ?)
+		ON CONFLICT (pokemon) DO UPDATE SET
+			collected = excluded.collected, caught_at = excluded.caught_at, attempts = excluded.attempts`,
+		name, entry.Collected, caughtAt, entry.Attempts)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) List() ([]pokedex.Entry, error) {
+	return s.entries("SELECT " + entryColumns)
+}
+
+func (s *Store) entries(query string, args ...any) ([]pokedex.Entry, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []pokedex.Entry
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// orderBy maps sort keys to ORDER BY clauses matching pokedex.Query's
+// in-memory order: ties, and unknown keys, fall back to the name.
+var orderBy = map[pokedex.SortKey]string{
+	pokedex.SortByID:       "p.id, ",
+	pokedex.SortByCaughtAt: "c.caught_at, ",
+	pokedex.SortByBST:      "(SELECT SUM(base_stat) FROM pokemon_stats WHERE pokemon = p.name) DESC, ",
+}
+
+// Query runs q in the database.
+func (s *Store) Query(q pokedex.Query) ([]pokedex.Entry, error) {
+	where := []string{"c.collected"}
+	var args []any
+	for _, typeName := range q.Types {
+		where = append(where, `EXISTS (SELECT 1 FROM pokemon_types pt JOIN types t ON t.id = pt.type_id
+			WHERE pt.pokemon = p.name AND t.name = ?)`)
+		args = append(args, typeName)
+	}
+	for stat, minimum := range q.MinStats {
+		where = append(where, `EXISTS (SELECT 1 FROM pokemon_stats ps JOIN stats s ON s.id = ps.stat_id
+			WHERE ps.pokemon = p.name AND s.name = ? AND ps.base_stat >= ?)`)
+		args = append(args, stat, minimum)
+	}
+	query := "SELECT " + entryColumns +
+		" WHERE " + strings.Join(where, " AND ") +
+		" ORDER BY " + orderBy[q.Sort] + "p.name"
+	if q.Limit > 0 || q.Offset > 0 {
+		// SQLite needs a LIMIT to use OFFSET; -1 means none.
+		limit := -1
+		if q.Limit > 0 {
+			limit = q.Limit
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, max(q.Offset, 0))
+	}
+	return s.entries(query, args...)
+}
+
+// APICache returns a pokeapi.ResponseCache that saves responses in the
+// database.
+func (s *Store) APICache() pokeapi.ResponseCache {
+	return apiCache{s.db}
+}
+
+type apiCache struct {
+	db *sql.DB
+}
+
+func (c apiCache) Get(url string) ([]byte, time.Time, bool) {
+	var body []byte
+	var fetchedAt int64
+	row := c.db.QueryRow("SELECT body, fetched_at FROM api_responses WHERE url = ?", url)
+	if err := row.Scan(&body, &fetchedAt); err != nil {
+		return nil, time.Time{}, false
+	}
+	return body, time.Unix(0, fetchedAt), true
+}
+
+// Add saves a response. Failures are ignored, as the response can always
+// be fetched again.
+func (c apiCache) Add(url string, body []byte) {
+	c.db.Exec(`INSERT INTO api_responses (url, body, fetched_at) VALUES (?, ?, ?)
+		ON CONFLICT (url) DO UPDATE SET body = excluded.body, fetched_at = excluded.fetched_at`,
+		url, body, time.Now().UnixNano())
+}
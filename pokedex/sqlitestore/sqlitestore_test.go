@@ -0,0 +1,199 @@
+package sqlitestore
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shamsup/pokedexcli/internal/pokeapitest"
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+)
+
+var alwaysCatch = pokedex.CatchPolicyFunc(func(pokeapi.PokemonDetails) bool { return true })
+
+func openTestStore(t *testing.T, path string) *Store {
+	t.Helper()
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// catchAll catches the same Pokemon, a minute apart, in a Pokedex backed by
+// store.
+func catchAll(t *testing.T, store pokedex.Store) *pokedex.Pokedex {
+	t.Helper()
+	now := time.Date(2024, time.August, 1, 12, 0, 0, 0, time.UTC)
+	p := pokedex.NewPokedex(
+		pokedex.WithStore(store),
+		pokedex.WithCatchPolicy(alwaysCatch),
+		pokedex.WithClock(func() time.Time {
+			now = now.Add(time.Minute)
+			return now
+		}),
+	)
+	for _, name := range []string{"pikachu", "magikarp", "charmander", "farfetchd", "mr-mime"} {
+		if _, _, err := p.CatchPokemon(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func names(entries []pokedex.Entry) []string {
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Pokemon.Name)
+	}
+	return names
+}
+
+func TestQueryMatchesMemoryStore(t *testing.T) {
+	pokeapitest.NewServer(t)
+	inMemory := catchAll(t, pokedex.NewMemoryStore())
+	inSQLite := catchAll(t, openTestStore(t, filepath.Join(t.TempDir(), "pokedex.db")))
+
+	queries := []pokedex.Query{
+		{},
+		{Sort: pokedex.SortByID},
+		{Sort: pokedex.SortByCaughtAt},
+		{Sort: pokedex.SortByBST},
+		{Types: []string{"fire"}},
+		{Types: []string{"normal", "flying"}},
+		{MinStats: map[string]int{"speed": 80}},
+		{MinStats: map[string]int{"speed": 80, "attack": 60}},
+		{Sort: pokedex.SortByBST, Limit: 2},
+		{Sort: pokedex.SortByID, Offset: 2},
+		{Sort: pokedex.SortByID, Offset: 1, Limit: 2},
+		{Types: []string{"dragon"}},
+	}
+	for _, q := range queries {
+		want, err := inMemory.Query(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := inSQLite.Query(q)
+		if err != nil {
+			t.Fatalf("%+v: %v", q, err)
+		}
+		if !slices.Equal(names(got), names(want)) {
+			t.Errorf("%+v: expected %v, got %v", q, names(want), names(got))
+		}
+	}
+}
+
+func TestStorePersists(t *testing.T) {
+	pokeapitest.NewServer(t)
+	path := filepath.Join(t.TempDir(), "pokedex.db")
+	store := openTestStore(t, path)
+	catchAll(t, store)
+	store.Close()
+
+	reopened := openTestStore(t, path)
+	entry, ok, err := reopened.Get("pikachu")
+	if err != nil || !ok {
+		t.Fatalf("expected pikachu to be saved, got %v, %v", ok, err)
+	}
+	caughtAt := time.Date(2024, time.August, 1, 12, 1, 0, 0, time.UTC)
+	if !entry.Collected || !entry.CaughtAt.Equal(caughtAt) || entry.Attempts != 1 || entry.Pokemon.ID != 25 {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if _, ok, err := reopened.Get("bulbasaur"); ok || err != nil {
+		t.Errorf("expected no bulbasaur, got %v, %v", ok, err)
+	}
+	entries, err := reopened.List()
+	if err != nil || len(entries) != 5 {
+		t.Errorf("expected 5 entries, got %d, %v", len(entries), err)
+	}
+
+	// A missed throw is saved without a catch time.
+	miss := pokedex.CatchPolicyFunc(func(pokeapi.PokemonDetails) bool { return false })
+	p := pokedex.NewPokedex(pokedex.WithStore(reopened), pokedex.WithCatchPolicy(miss))
+	if _, _, err := p.CatchPokemon("deoxys-normal"); err != nil {
+		t.Fatal(err)
+	}
+	if entry, ok, _ := reopened.Get("deoxys-normal"); !ok || entry.Collected || !entry.CaughtAt.IsZero() || entry.Attempts != 1 {
+		t.Errorf("expected an uncaught entry, got %+v", entry)
+	}
+}
+
+func TestImport(t *testing.T) {
+	pokeapitest.NewServer(t)
+	path := filepath.Join(t.TempDir(), "pokedex.db")
+	store := openTestStore(t, path)
+	catchAll(t, store)
+	miss := pokedex.CatchPolicyFunc(func(pokeapi.PokemonDetails) bool { return false })
+	if _, _, err := pokedex.NewPokedex(pokedex.WithStore(store), pokedex.WithCatchPolicy(miss)).CatchPokemon("deoxys-normal"); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := Import(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 || slices.ContainsFunc(records, func(r pokedex.Record) bool { return r.Name == "deoxys-normal" }) {
+		t.Errorf("expected the 5 caught Pokemon, got %+v", records)
+	}
+	caughtAt := time.Date(2024, time.August, 1, 12, 1, 0, 0, time.UTC)
+	for _, record := range records {
+		if record.Name == "pikachu" && (!record.CaughtAt.Equal(caughtAt) || record.ID != 25) {
+			t.Errorf("expected pikachu's catch details, got %+v", record)
+		}
+	}
+
+	if _, err := Import(strings.NewReader("id,name\n25,pikachu\n")); err == nil {
+		t.Errorf("expected a CSV file to be refused")
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.db")
+	openTestStore(t, path).Close()
+	// Opening again applies nothing new.
+	store := openTestStore(t, path)
+	var version int
+	if err := store.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil || version != len(migrations) {
+		t.Errorf("expected schema version %d, got %d, %v", len(migrations), version, err)
+	}
+	store.Close()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Exec("PRAGMA user_version = 99")
+	db.Close()
+	if _, err := Open(path); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("expected a newer schema to be refused, got %v", err)
+	}
+}
+
+func TestAPICache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.db")
+	cache := openTestStore(t, path).APICache()
+	if _, _, ok := cache.Get("https://pokeapi.co/api/v2/pokemon/25"); ok {
+		t.Errorf("expected an empty cache")
+	}
+	cache.Add("https://pokeapi.co/api/v2/pokemon/25", []byte(`{"id": 25}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon/25", []byte(`{"id": 25, "name": "pikachu"}`))
+	body, fetchedAt, ok := openTestStore(t, path).APICache().Get("https://pokeapi.co/api/v2/pokemon/25")
+	if !ok || string(body) != `{"id": 25, "name": "pikachu"}` {
+		t.Errorf("expected the latest response, got %q, %v", body, ok)
+	}
+	if age := time.Since(fetchedAt); age < 0 || age > time.Minute {
+		t.Errorf("expected the response to have just been fetched, got %v", fetchedAt)
+	}
+}
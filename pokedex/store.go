@@ -0,0 +1,56 @@
+package pokedex
+
+import "sync"
+
+// Store keeps Pokedex entries keyed by Pokemon name. Entries it returns
+// must not share memory with the ones it keeps, so callers may change them.
+type Store interface {
+	Get(name string) (Entry, bool, error)
+	Put(name string, entry Entry) error
+	List() ([]Entry, error)
+}
+
+// Querier is a Store that can run a Query itself, such as a database with
+// indexes, instead of the Pokedex filtering every entry. It must return
+// the same results as Pokedex.Query does for a plain Store.
+type Querier interface {
+	Query(q Query) ([]Entry, error)
+}
+
+// MemoryStore is a Store that keeps entries in memory for the life of the
+// process. It keeps and returns copies of entries.
+type MemoryStore struct {
+	entries map[string]Entry
+	mu      *sync.RWMutex
+}
+
+func NewMemoryStore() MemoryStore {
+	return MemoryStore{
+		entries: map[string]Entry{},
+		mu:      &sync.RWMutex{},
+	}
+}
+
+func (s MemoryStore) Get(name string) (Entry, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[name]
+	return entry.clone(), ok, nil
+}
+
+func (s MemoryStore) Put(name string, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[name] = entry.clone()
+	return nil
+}
+
+func (s MemoryStore) List() ([]Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry.clone())
+	}
+	return entries, nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shamsup/pokedexcli/internal/termout"
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+const (
+	defaultPrefetchWorkers = 8
+	// maxPrefetchWorkers keeps prefetch within the PokeAPI's fair use.
+	maxPrefetchWorkers = 32
+)
+
+// prefetchGroups are the list endpoints each prefetch target walks.
+var prefetchGroups = map[string][]string{
+	"pokemon":   {"pokemon/", "pokemon-species/"},
+	"locations": {"region/", "location/", "location-area/"},
+	"all": {
+		"pokemon/", "pokemon-species/",
+		"region/", "location/", "location-area/",
+		"type/", "move/", "version/", "version-group/", "language/",
+	},
+}
+
+func prefetchTargets() []string {
+	targets := make([]string, 0, len(prefetchGroups))
+	for target := range prefetchGroups {
+		targets = append(targets, target)
+	}
+	slices.Sort(targets)
+	return targets
+}
+
+// prefetchResult counts what happened to each URL.
+type prefetchResult struct {
+	fetched, saved, missing, failed int
+	firstErr                        error
+}
+
+func (r *prefetchResult) add(fetched bool, err error) {
+	switch {
+	case errors.Is(err, pokeapi.ErrNotFound):
+		r.missing++
+	case err != nil:
+		r.failed++
+		if r.firstErr == nil {
+			r.firstErr = err
+		}
+	case fetched:
+		r.fetched++
+	default:
+		r.saved++
+	}
+}
+
+func commandPrefetch(c *Config, args Args) error {
+	target := args.Positional[0]
+	resources, ok := prefetchGroups[target]
+	if !ok {
+		return fmt.Errorf("can't prefetch %q: expected one of %s.%s", target, strings.Join(prefetchTargets(), ", "), didYouMean(target, prefetchTargets()))
+	}
+	workers, err := args.Int("concurrency", defaultPrefetchWorkers)
+	if err != nil {
+		return err
+	}
+	if workers < 1 || workers > maxPrefetchWorkers {
+		return fmt.Errorf("--concurrency must be between 1 and %d", maxPrefetchWorkers)
+	}
+	if !pokeapi.HasPersistentCache() {
+		return errors.New("prefetch saves to the database, but pokedexcli was started without one (--db)")
+	}
+
+	// Ctrl-C stops the prefetch rather than pokedexcli. Everything fetched
+	// so far is saved, so running it again picks up where it left off.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var total prefetchResult
+	for _, resource := range resources {
+		urls, err := pokeapi.DetailURLs(resource)
+		if err != nil {
+			fmt.Fprintf(c.Out, "%s: couldn't list: %v\n", resource, err)
+			total.add(false, err)
+			continue
+		}
+		bar := newProgressBar(c.Out, strings.TrimSuffix(resource, "/"), len(urls))
+		result := prefetchAll(ctx, urls, workers, bar)
+		bar.finish()
+		total.fetched += result.fetched
+		total.saved += result.saved
+		total.missing += result.missing
+		total.failed += result.failed
+		if total.firstErr == nil {
+			total.firstErr = result.firstErr
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if ctx.Err() == nil && slices.Contains(resources, "pokemon/") {
+		index, err := pokeapi.BuildStatsIndex()
+		if err != nil {
+			fmt.Fprintf(c.Out, "Couldn't rank stats: %v\n", err)
+		} else {
+			fmt.Fprintf(c.Out, "Ranked the base stats of %d species\n", index.Count)
+		}
+	}
+
+	fmt.Fprintf(c.Out, "Fetched %d, %d already saved", total.fetched, total.saved)
+	if total.missing > 0 {
+		fmt.Fprintf(c.Out, ", %d not found", total.missing)
+	}
+	if total.failed > 0 {
+		fmt.Fprintf(c.Out, ", %d failed (%v)", total.failed, total.firstErr)
+	}
+	fmt.Fprintln(c.Out)
+	if ctx.Err() != nil {
+		fmt.Fprintf(c.Out, "Interrupted. Run 'prefetch %s' again to resume.\n", target)
+	}
+	return nil
+}
+
+// prefetchAll fetches urls with a bounded pool of workers until they're
+// done or ctx is cancelled.
+func prefetchAll(ctx context.Context, urls []string, workers int, bar *progressBar) prefetchResult {
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var result prefetchResult
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range jobs {
+				fetched, err := pokeapi.Prefetch(url)
+				mu.Lock()
+				result.add(fetched, err)
+				mu.Unlock()
+				bar.add(fetched)
+			}
+		}()
+	}
+feed:
+	for _, url := range urls {
+		select {
+		case jobs <- url:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return result
+}
+
+// progressRedraw limits how often a live progress bar is redrawn.
+const progressRedraw = 100 * time.Millisecond
+
+const progressBarWidth = 30
+
+// progressBar shows how far through a list prefetch is. On a terminal it
+// is redrawn in place; elsewhere only the final count is written.
+type progressBar struct {
+	out   io.Writer
+	live  bool
+	label string
+	total int
+	start time.Time
+
+	mu       sync.Mutex
+	done     int
+	fetched  int
+	lastDraw time.Time
+}
+
+func newProgressBar(out *termout.Writer, label string, total int) *progressBar {
+	if out.TTY {
+		out.Live()
+	}
+	return &progressBar{
+		out:   out,
+		live:  out.TTY,
+		label: label,
+		total: total,
+		start: time.Now(),
+	}
+}
+
+// add records one finished URL, which was fetched rather than already
+// saved if fetched is true.
+func (b *progressBar) add(fetched bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done++
+	if fetched {
+		b.fetched++
+	}
+	if b.live && time.Since(b.lastDraw) >= progressRedraw {
+		b.lastDraw = time.Now()
+		fmt.Fprint(b.out, "\r"+b.line()+"\x1b[K")
+	}
+}
+
+func (b *progressBar) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.live {
+		fmt.Fprint(b.out, "\r"+b.line()+"\x1b[K\n")
+		return
+	}
+	fmt.Fprintf(b.out, "%s: %d/%d\n", b.label, b.done, b.total)
+}
+
+// line renders the bar, e.g. "pokemon [#####-----] 50% 650/1302 ETA 1m5s".
+func (b *progressBar) line() string {
+	fraction := 1.0
+	if b.total > 0 {
+		fraction = float64(b.done) / float64(b.total)
+	}
+	filled := int(fraction * progressBarWidth)
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled)
+	return fmt.Sprintf("%s [%s] %3.0f%% %d/%d ETA %s", b.label, bar, fraction*100, b.done, b.total, b.eta())
+}
+
+// eta estimates the time left from how long fetching has taken so far.
+// Already saved responses take no time, so only fetches count.
+func (b *progressBar) eta() string {
+	remaining := b.total - b.done
+	if remaining == 0 {
+		return "0s"
+	}
+	if b.fetched == 0 {
+		return "--"
+	}
+	perFetch := time.Since(b.start) / time.Duration(b.fetched)
+	return (perFetch * time.Duration(remaining)).Round(time.Second).String()
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+const (
+	dataDirName     = ".pokedexcli"
+	profileFileName = "profile.json"
+	// assetDirName holds downloaded sprites and cries.
+	assetDirName = "assets"
+	// allVersions clears the selected version.
+	allVersions = "all"
+)
+
+// Profile is the trainer playing: their name and the game they're playing,
+// which filters what the commands show. It is saved between sessions.
+type Profile struct {
+	Name string `json:"name"`
+	// Version is the selected game, such as "platinum", or "" for none.
+	Version string `json:"version"`
+}
+
+// dataDir is where pokedexcli keeps its files, or "" if there is no home
+// directory.
+func dataDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, dataDirName)
+}
+
+func profilePath() string {
+	dir := dataDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, profileFileName)
+}
+
+// loadProfile reads a saved profile. A missing file is an empty profile.
+func loadProfile(path string) (Profile, error) {
+	var profile Profile
+	if path == "" {
+		return profile, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return profile, nil
+	}
+	if err != nil {
+		return profile, err
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return profile, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return profile, nil
+}
+
+func (p Profile) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// saveProfile writes the profile if the session has somewhere to keep it.
+func (c *Config) saveProfile() error {
+	if err := c.Profile.save(c.ProfilePath); err != nil {
+		return fmt.Errorf("saving profile: %w", err)
+	}
+	return nil
+}
+
+// game describes the selected game version.
+type game struct {
+	Version    string
+	Group      string
+	Generation int
+	Regions    []string
+}
+
+// game looks up the selected version's group and generation. It returns
+// false if no version is selected or it can't be looked up.
+func (c *Config) game() (game, bool) {
+	if c.Profile.Version == "" {
+		return game{}, false
+	}
+	return lookupGame(c.Profile.Version)
+}
+
+func lookupGame(version string) (game, bool) {
+	v, err := pokeapi.GetVersion(version)
+	if err != nil {
+		return game{}, false
+	}
+	group, err := pokeapi.GetVersionGroup(v.VersionGroup.Name)
+	if err != nil {
+		return game{}, false
+	}
+	g := game{
+		Version:    version,
+		Group:      group.Name,
+		Generation: pokeapi.GenerationNumber(group.Generation.Name),
+	}
+	for _, region := range group.Regions {
+		g.Regions = append(g.Regions, region.Name)
+	}
+	return g, true
+}
+
+// resolveVersion checks a game's name against the versions the PokeAPI
+// knows. If the list can't be fetched the name is accepted as typed.
+func resolveVersion(version string) (string, error) {
+	versions, err := pokeapi.VersionNames()
+	if err != nil {
+		return version, nil
+	}
+	if !slices.Contains(versions, version) {
+		return "", fmt.Errorf("no game version named %q.%s", version, didYouMean(version, versions))
+	}
+	return version, nil
+}
+
+// versionFlag returns the game a command should show: the --version flag if
+// given, otherwise the profile's selected version. "--version all" shows
+// every game.
+func (c *Config) versionFlag(args Args) string {
+	if !args.Has("version") {
+		return c.Profile.Version
+	}
+	if version := args.Flag("version"); version != allVersions {
+		return version
+	}
+	return ""
+}
+
+// typeNames returns a Pokemon's types as they are in the selected game.
+func (c *Config) typeNames(p pokeapi.PokemonDetails) []string {
+	g, _ := c.game()
+	return p.TypeNamesIn(g.Generation)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", profileFileName)
+
+	profile, err := loadProfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile != (Profile{}) {
+		t.Errorf("expected an empty profile before saving, got %+v", profile)
+	}
+
+	want := Profile{Name: "Dawn", Version: "platinum"}
+	if err := want.save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadProfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var errUnknownCommand = errors.New("unknown command")
+
+// ArgKind describes what a command argument refers to. Everything except
+// ArgString is an identifier and is lowercased when parsed.
+type ArgKind int
+
+const (
+	ArgString ArgKind = iota
+	ArgPokemon
+	ArgLocation
+	// ArgKeyword is any other PokeAPI name or fixed choice, such as a type
+	// or a sort order.
+	ArgKeyword
+)
+
+// ArgSpec describes one positional argument of a command.
+type ArgSpec struct {
+	Name        string
+	Kind        ArgKind
+	Description string
+	Optional    bool
+	// Variadic accepts any number of trailing arguments. Only the last
+	// argument may be variadic.
+	Variadic bool
+	// Rest joins all remaining words into this argument, so multi-word
+	// names don't need quoting. Only the last argument may use Rest.
+	Rest bool
+}
+
+type Command struct {
+	Name        string
+	Description string
+	// Usage overrides the usage line generated from Args.
+	Usage    string
+	Category string
+	Aliases  []string
+	Args     []ArgSpec
+	Flags    []FlagSpec
+	Examples []string
+	Handler  func(c *Config, args Args) error
+	Config   *Config
+}
+
+// UsageLine returns how to invoke the command, e.g. "explore <area>".
+func (cmd Command) UsageLine() string {
+	if cmd.Usage != "" {
+		return cmd.Usage
+	}
+	parts := []string{cmd.Name}
+	for _, flag := range cmd.Flags {
+		if flag.Bool {
+			parts = append(parts, "[--"+flag.Name+"]")
+		} else {
+			parts = append(parts, "[--"+flag.Name+" <"+flagValue(flag)+">]")
+		}
+	}
+	for _, arg := range cmd.Args {
+		name := arg.Name
+		if arg.Variadic {
+			name += "..."
+		}
+		if arg.Optional || arg.Variadic {
+			parts = append(parts, "["+name+"]")
+		} else {
+			parts = append(parts, "<"+name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func flagValue(flag FlagSpec) string {
+	if flag.Value != "" {
+		return flag.Value
+	}
+	return "value"
+}
+
+// validate checks args against the command's ArgSpecs.
+func (cmd Command) validate(args []string) error {
+	required := 0
+	variadic := false
+	for _, arg := range cmd.Args {
+		if !arg.Optional && !arg.Variadic {
+			required++
+		}
+		variadic = variadic || arg.Variadic
+	}
+	if len(args) < required {
+		missing := cmd.Args[len(args)]
+		return fmt.Errorf("expected %s\nusage: %s", missing.Name, cmd.UsageLine())
+	}
+	if !variadic && len(args) > len(cmd.Args) {
+		return fmt.Errorf("unexpected argument %q\nusage: %s", args[len(cmd.Args)], cmd.UsageLine())
+	}
+	return nil
+}
+
+// Registry holds the REPL's commands and resolves aliases.
+type Registry struct {
+	commands map[string]Command
+	aliases  map[string]string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		commands: map[string]Command{},
+		aliases:  map[string]string{},
+	}
+}
+
+// Register adds a command. Registering a name or alias twice is a
+// programming error and panics.
+func (r *Registry) Register(cmd Command) {
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if _, ok := r.Lookup(name); ok {
+			panic(fmt.Sprintf("command %q registered twice", name))
+		}
+	}
+	r.commands[cmd.Name] = cmd
+	for _, alias := range cmd.Aliases {
+		r.aliases[alias] = cmd.Name
+	}
+}
+
+// Lookup finds a command by name or alias.
+func (r *Registry) Lookup(name string) (Command, bool) {
+	if target, ok := r.aliases[name]; ok {
+		name = target
+	}
+	cmd, ok := r.commands[name]
+	return cmd, ok
+}
+
+// Commands returns every command sorted by name.
+func (r *Registry) Commands() []Command {
+	cmds := make([]Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		cmds = append(cmds, cmd)
+	}
+	slices.SortFunc(cmds, func(a, b Command) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return cmds
+}
+
+// Names returns every command name and alias, sorted.
+func (r *Registry) Names() []string {
+	var names []string
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	for alias := range r.aliases {
+		names = append(names, alias)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Dispatch parses and validates the tokens following a command name, then
+// runs the command.
+func (r *Registry) Dispatch(name string, tokens []string) error {
+	cmd, ok := r.Lookup(strings.ToLower(name))
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownCommand, name)
+	}
+	args, err := cmd.parseArgs(tokens)
+	if err != nil {
+		return err
+	}
+	if err := cmd.validate(args.Positional); err != nil {
+		return err
+	}
+	return cmd.Handler(cmd.Config, args)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shamsup/pokedexcli/internal/termout"
+)
+
+func TestRegistryDispatch(t *testing.T) {
+	var got []string
+	r := NewRegistry()
+	r.Register(Command{
+		Name:    "catch",
+		Aliases: []string{"c"},
+		Args:    []ArgSpec{{Name: "pokemon", Kind: ArgPokemon}},
+		Handler: func(_ *Config, args Args) error {
+			got = args.Positional
+			return nil
+		},
+	})
+	r.Register(Command{
+		Name:  "pokedex",
+		Args:  []ArgSpec{{Name: "names", Variadic: true}},
+		Flags: []FlagSpec{{Name: "sort"}},
+		Handler: func(_ *Config, args Args) error {
+			got = args.Positional
+			return nil
+		},
+	})
+
+	cases := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"catch", []string{"pikachu"}, ""},
+		{"c", []string{"pikachu"}, ""},
+		{"catch", nil, "expected pokemon"},
+		{"catch", []string{"pikachu", "bulbasaur"}, `unexpected argument "bulbasaur"`},
+		{"pokedex", nil, ""},
+		{"pokedex", []string{"a", "b"}, ""},
+		{"throw", nil, "unknown command"},
+	}
+	for _, c := range cases {
+		got = nil
+		err := r.Dispatch(c.name, c.args)
+		if c.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s %v: unexpected error %v", c.name, c.args, err)
+			} else if !slices.Equal(got, c.args) {
+				t.Errorf("%s %v: handler got %v", c.name, c.args, got)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+			t.Errorf("%s %v: expected error containing %q, got %v", c.name, c.args, c.wantErr, err)
+		}
+		if got != nil {
+			t.Errorf("%s %v: handler should not run on invalid input", c.name, c.args)
+		}
+	}
+	if err := r.Dispatch("throw", nil); !errors.Is(err, errUnknownCommand) {
+		t.Errorf("expected errUnknownCommand, got %v", err)
+	}
+}
+
+func TestRegistryRejectsDuplicates(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Command{Name: "exit", Aliases: []string{"quit"}})
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected registering a duplicate alias to panic")
+		}
+	}()
+	r.Register(Command{Name: "quit"})
+}
+
+func TestUsageLine(t *testing.T) {
+	cmd := Command{
+		Name: "where",
+		Args: []ArgSpec{
+			{Name: "pokemon"},
+			{Name: "version", Optional: true},
+			{Name: "more", Variadic: true},
+		},
+	}
+	if usage := cmd.UsageLine(); usage != "where <pokemon> [version] [more...]" {
+		t.Errorf("unexpected usage %q", usage)
+	}
+}
+
+func TestHelp(t *testing.T) {
+	previous := commands
+	commands = NewRegistry()
+	t.Cleanup(func() { commands = previous })
+	out := &bytes.Buffer{}
+	c := &Config{Out: termout.New(out)}
+	registerCommands(commands, c)
+
+	if err := commandHelp(c, Args{}); err != nil {
+		t.Fatal(err)
+	}
+	help := out.String()
+	general := strings.Index(help, "General:")
+	exploration := strings.Index(help, "Exploration:")
+	pokemon := strings.Index(help, "Pokemon:")
+	if general < 0 || !(general < exploration && exploration < pokemon) {
+		t.Errorf("expected categories in order, got:\n%s", help)
+	}
+	if strings.Index(help, "  map ") > strings.Index(help, "  mapb ") {
+		t.Errorf("expected commands sorted within a category, got:\n%s", help)
+	}
+
+	out.Reset()
+	if err := commandHelp(c, NewArgs([]string{"dex"}, nil)); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"pokedex: List caught Pokemon", "Usage: pokedex [--sort", "Aliases: dex", "Examples:\n  pokedex\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	if err := commandHelp(c, NewArgs([]string{"fly"}, nil)); !errors.Is(err, errUnknownCommand) {
+		t.Errorf("expected errUnknownCommand, got %v", err)
+	}
+}
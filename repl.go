@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shamsup/pokedexcli/internal/lineedit"
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+const (
+	prompt          = "Pokedex > "
+	historyFileName = ".pokedexcli_history"
+)
+
+func runREPL(c *Config) {
+	reader := newLineReader(c)
+	for {
+		line, err := reader.ReadLine(prompt)
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			continue
+		}
+		if err != nil {
+			// End of input behaves like the exit command.
+			commandExit(c, Args{})
+			return
+		}
+		words, err := tokenize(line)
+		if err != nil {
+			printError(c, err)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		err = c.Out.Page(func() error {
+			return commands.Dispatch(words[0], words[1:])
+		})
+		if errors.Is(err, errExit) {
+			return
+		}
+		if errors.Is(err, errUnknownCommand) {
+			fmt.Fprintf(c.Out, "%s%s\n", c.Out.Paint("Unknown command.", errorStyle), didYouMean(words[0], commands.Names()))
+		} else if err != nil {
+			printError(c, err)
+		}
+	}
+}
+
+func printError(c *Config, err error) {
+	fmt.Fprintln(c.Out, c.Out.Paint("Error:", errorStyle), err)
+}
+
+// cleanInput splits and lowercases a line for matching, e.g. in tab
+// completion. Commands are parsed with tokenize instead.
+func cleanInput(text string) []string {
+	words := []string{}
+	for _, word := range strings.Fields(text) {
+		if word != "" {
+			words = append(words, strings.ToLower(word))
+		}
+	}
+	return words
+}
+
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+// newLineReader uses the line editor when stdin is a terminal and falls back
+// to plain line reading for pipes and files.
+func newLineReader(c *Config) lineReader {
+	if !lineedit.IsTerminal(os.Stdin) {
+		return &scannerReader{scanner: bufio.NewScanner(os.Stdin), out: c.Out}
+	}
+	history := lineedit.NewHistory(historyPath(), 0)
+	if err := history.Load(); err != nil {
+		fmt.Fprintln(c.Out, "Could not load history:", err)
+	}
+	return &terminalReader{
+		editor: lineedit.New(os.Stdin, os.Stdout, history, completer(c)),
+	}
+}
+
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, historyFileName)
+}
+
+type scannerReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+func (r *scannerReader) ReadLine(prompt string) (string, error) {
+	fmt.Fprint(r.out, prompt)
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// terminalReader switches the terminal to raw mode only while a line is
+// being edited, so command output is printed normally.
+type terminalReader struct {
+	editor *lineedit.Editor
+}
+
+func (r *terminalReader) ReadLine(prompt string) (string, error) {
+	restore, err := lineedit.MakeRaw(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+	return r.editor.ReadLine(prompt)
+}
+
+// completer completes command names, then arguments for commands that take
+// a location or a caught Pokemon.
+func completer(c *Config) lineedit.Completer {
+	return func(line string) []string {
+		words := cleanInput(line)
+		prefix := ""
+		if len(words) > 0 && !strings.HasSuffix(line, " ") {
+			prefix = words[len(words)-1]
+			words = words[:len(words)-1]
+		}
+
+		var options []string
+		switch {
+		case len(words) == 0:
+			options = commands.Names()
+		case len(words) > 1:
+			// Every completable command takes a single argument.
+		case words[0] == "explore":
+			options = c.SeenLocations
+		case words[0] == "inspect":
+			options, _ = c.Pokedex.ListCaughtPokemon()
+		case words[0] == "region":
+			options, _ = pokeapi.RegionNames()
+		case words[0] == "areas":
+			options, _ = pokeapi.LocationNames()
+		}
+
+		var matches []string
+		for _, option := range options {
+			if strings.HasPrefix(option, prefix) {
+				matches = append(matches, option)
+			}
+		}
+		slices.Sort(matches)
+		return matches
+	}
+}
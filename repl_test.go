@@ -1,6 +1,11 @@
 package main
 
-import "testing"
+import (
+	"slices"
+	"testing"
+
+	"github.com/shamsup/pokedexcli/pokedex"
+)
 
 func TestCleanInput(t *testing.T) {
 	cases := []struct {
@@ -35,3 +40,84 @@ func TestCleanInput(t *testing.T) {
 		}
 	}
 }
+
+func TestParsePokedexQuery(t *testing.T) {
+	r := NewRegistry()
+	registerCommands(r, nil)
+	cmd, _ := r.Lookup("pokedex")
+
+	parse := func(line string) (pokedex.Query, error) {
+		tokens, err := tokenize(line)
+		if err != nil {
+			return pokedex.Query{}, err
+		}
+		args, err := cmd.parseArgs(tokens)
+		if err != nil {
+			return pokedex.Query{}, err
+		}
+		return parsePokedexQuery(args)
+	}
+
+	query, err := parse("--sort bst --type fire --type Flying --min-stat attack=80 --limit=20 --offset 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Sort != pokedex.SortByBST {
+		t.Errorf("expected sort bst, got %v", query.Sort)
+	}
+	if len(query.Types) != 2 || query.Types[0] != "fire" || query.Types[1] != "flying" {
+		t.Errorf("expected types [fire flying], got %v", query.Types)
+	}
+	if query.MinStats["attack"] != 80 {
+		t.Errorf("expected attack >= 80, got %v", query.MinStats)
+	}
+	if query.Limit != 20 || query.Offset != 5 {
+		t.Errorf("expected limit 20 offset 5, got %d %d", query.Limit, query.Offset)
+	}
+
+	for _, line := range []string{
+		"--sort weight",
+		"--min-stat attack",
+		"--limit -1",
+		"--limit ten",
+		"--sort id --sort name",
+	} {
+		if _, err := parse(line); err == nil {
+			t.Errorf("expected an error for %q", line)
+		}
+	}
+}
+
+func TestCompleter(t *testing.T) {
+	c := newTestREPL(t).c
+	if _, _, err := c.Pokedex.CatchPokemon("pikachu"); err != nil {
+		t.Fatal(err)
+	}
+	c.SeenLocations = []string{"eterna-city-area", "canalave-city-area", "eterna-forest-area"}
+	previous := commands
+	commands = NewRegistry()
+	commands.Register(Command{Name: "explore"})
+	commands.Register(Command{Name: "exit", Aliases: []string{"quit"}})
+	commands.Register(Command{Name: "inspect"})
+	t.Cleanup(func() { commands = previous })
+
+	complete := completer(c)
+	cases := []struct {
+		line     string
+		expected []string
+	}{
+		{"", []string{"exit", "explore", "inspect", "quit"}},
+		{"ex", []string{"exit", "explore"}},
+		{"explore ", []string{"canalave-city-area", "eterna-city-area", "eterna-forest-area"}},
+		{"explore Eterna-", []string{"eterna-city-area", "eterna-forest-area"}},
+		{"inspect p", []string{"pikachu"}},
+		{"inspect pikachu ", nil},
+		{"catch ", nil},
+	}
+	for _, c := range cases {
+		actual := complete(c.line)
+		if !slices.Equal(actual, c.expected) {
+			t.Errorf("%q: expected %v, got %v", c.line, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"regexp"
+	"strings"
+
+	"github.com/shamsup/pokedexcli/internal/termimg"
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+// spriteScale enlarges sprites drawn with the pixel protocols, whose pixels
+// are far smaller than a character cell.
+const spriteScale = 2
+
+// generationPrefix matches the optional "gen1-" in sprite games such as
+// gen1-red-blue.
+var generationPrefix = regexp.MustCompile(`^gen\d+-`)
+
+// spriteChoice is which sprite inspect --sprite asked for.
+type spriteChoice struct {
+	Back    bool
+	Shiny   bool
+	Version string
+	Group   string
+}
+
+// String describes the choice for messages, e.g. "shiny back".
+func (s spriteChoice) String() string {
+	var parts []string
+	if s.Shiny {
+		parts = append(parts, "shiny")
+	}
+	if s.Back {
+		parts = append(parts, "back")
+	} else {
+		parts = append(parts, "front")
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseSpriteChoice combines --sprite values. Each is front, back, shiny,
+// or a game such as platinum or gen1-red-blue; the game defaults to the
+// selected version.
+func (c *Config) parseSpriteChoice(values []string) (spriteChoice, error) {
+	var choice spriteChoice
+	if g, ok := c.game(); ok {
+		choice.Version, choice.Group = g.Version, g.Group
+	}
+	for _, value := range values {
+		switch value {
+		case "front", "default":
+			choice.Back = false
+		case "back":
+			choice.Back = true
+		case "shiny":
+			choice.Shiny = true
+		default:
+			name := generationPrefix.ReplaceAllString(value, "")
+			if g, ok := lookupGame(name); ok {
+				choice.Version, choice.Group = g.Version, g.Group
+			} else if group, err := pokeapi.GetVersionGroup(name); err == nil {
+				choice.Version, choice.Group = "", group.Name
+			} else {
+				return choice, fmt.Errorf("unknown sprite %q: expected front, back, shiny or a game such as gen1-red-blue", value)
+			}
+		}
+	}
+	return choice, nil
+}
+
+func (s spriteChoice) pick(set pokeapi.SpriteSet) string {
+	switch {
+	case s.Back && s.Shiny:
+		return set.BackShiny
+	case s.Back:
+		return set.Back
+	case s.Shiny:
+		return set.FrontShiny
+	default:
+		return set.Front
+	}
+}
+
+// spriteURL finds the chosen sprite. Older games lack shiny or back
+// sprites; then the current sprite is used and note explains why.
+func (s spriteChoice) spriteURL(pokemon pokeapi.PokemonDetails) (url, note string) {
+	if url := s.pick(pokemon.SpritesFor(s.Version, s.Group)); url != "" {
+		return url, ""
+	}
+	game := s.Group
+	if s.Version != "" {
+		game = s.Version
+	}
+	latest := pokeapi.SpriteSet{
+		Front:      pokemon.Sprites.FrontDefault,
+		Back:       pokemon.Sprites.BackDefault,
+		FrontShiny: pokemon.Sprites.FrontShiny,
+		BackShiny:  pokemon.Sprites.BackShiny,
+	}
+	url = s.pick(latest)
+	switch {
+	case url == "":
+		return "", fmt.Sprintf("No %s sprite for %s.", s, pokemon.Name)
+	case game != "":
+		return url, fmt.Sprintf("No %s sprite for %s in %s; showing the latest one.", s, pokemon.Name, game)
+	}
+	return url, ""
+}
+
+// drawSprite downloads a sprite and draws it with the terminal's graphics
+// protocol. Some sprites the PokeAPI lists were never drawn; those get a
+// note rather than an error.
+func (c *Config) drawSprite(url string) error {
+	if c.Graphics == termimg.None {
+		return nil
+	}
+	sprite, err := pokeapi.GetAsset(url)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		fmt.Fprintln(c.Out, "The sprite's image is missing.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	img, err := png.Decode(bytes.NewReader(sprite.Data))
+	if err != nil {
+		return fmt.Errorf("decoding sprite: %w", err)
+	}
+	c.Out.Live()
+	return termimg.Render(c.Out, termimg.Crop(img), c.Graphics, termimg.Options{
+		MaxWidth: c.Out.Width,
+		Scale:    spriteScale,
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shamsup/pokedexcli/internal/termout"
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+)
+
+const (
+	// statBarWidth is the length of a bar for the highest base stat.
+	statBarWidth = 24
+	// maxBaseStat is the highest base stat any Pokemon has.
+	maxBaseStat = 255
+)
+
+// barEighths draws the fraction of a cell at the end of a stat bar.
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// statBar draws value as a bar of full and partial blocks.
+func statBar(value int) string {
+	eighths := min(value, maxBaseStat) * statBarWidth * 8 / maxBaseStat
+	return strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+}
+
+// statColor colors a base stat from red for poor to cyan for exceptional.
+func statColor(value int) termout.Style {
+	switch {
+	case value < 30:
+		return termout.Red
+	case value < 60:
+		return termout.Color256(208)
+	case value < 90:
+		return termout.Yellow
+	case value < 120:
+		return termout.BrightGreen
+	case value < 150:
+		return termout.Green
+	}
+	return termout.Cyan
+}
+
+// statLabel is a stat's short name, such as "Sp. Atk".
+func statLabel(name string) string {
+	for _, stat := range compareStats {
+		if stat.name == name {
+			return stat.label
+		}
+	}
+	return name
+}
+
+// printStats draws a Pokemon's base stats as bars with their total and EV
+// yield. When a stats index has been built, each stat is also ranked
+// against every species.
+func (c *Config) printStats(pokemon pokeapi.PokemonDetails) {
+	index, ranked := pokeapi.LoadStatsIndex()
+	labelWidth := len("Total")
+	for _, stat := range pokemon.Stats {
+		labelWidth = max(labelWidth, len(statLabel(stat.Stat.Name)))
+	}
+	percentile := func(stat string, value int) string {
+		if p, ok := index.Percentile(stat, value); ranked && ok {
+			return fmt.Sprintf(" %3d%%", p)
+		}
+		return ""
+	}
+
+	fmt.Fprintln(c.Out, "Stats:")
+	var yield []string
+	for _, stat := range pokemon.Stats {
+		bar := c.Out.Paint(termout.Pad(statBar(stat.BaseStat), statBarWidth), statColor(stat.BaseStat))
+		line := fmt.Sprintf("  %-*s %3d %s%s", labelWidth, statLabel(stat.Stat.Name), stat.BaseStat, bar, percentile(stat.Stat.Name, stat.BaseStat))
+		fmt.Fprintln(c.Out, strings.TrimRight(line, " "))
+		if stat.Effort > 0 {
+			yield = append(yield, fmt.Sprintf("%d %s", stat.Effort, statLabel(stat.Stat.Name)))
+		}
+	}
+	total := pokedex.BaseStatTotal(pokemon)
+	line := fmt.Sprintf("  %-*s %3d %s%s", labelWidth, "Total", total, strings.Repeat(" ", statBarWidth), percentile(pokeapi.StatTotal, total))
+	fmt.Fprintln(c.Out, strings.TrimRight(line, " "))
+	if len(yield) > 0 {
+		fmt.Fprintf(c.Out, "EV yield: %s\n", strings.Join(yield, ", "))
+	}
+	if ranked {
+		fmt.Fprintf(c.Out, "Percentiles are the share of %d species with a lower base stat.\n", index.Count)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+	"github.com/shamsup/pokedexcli/pokedex/sqlitestore"
+)
+
+// databaseFileName holds the Pokedex and cached PokeAPI responses.
+const databaseFileName = "pokedex.db"
+
+// databasePath is the default --db, or "" if there is no home directory.
+func databasePath() string {
+	dir := dataDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, databaseFileName)
+}
+
+// openPokedex returns a Pokedex saved in the database at path, which also
+// caches PokeAPI responses. An empty path keeps everything in memory. The
+// returned function closes the database.
+func openPokedex(path string, opts ...pokedex.Option) (*pokedex.Pokedex, func(), error) {
+	if path == "" {
+		return pokedex.NewPokedex(opts...), func() {}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, nil, err
+	}
+	store, err := sqlitestore.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	pokeapi.SetPersistentCache(store.APICache())
+	opts = append(opts, pokedex.WithStore(store))
+	return pokedex.NewPokedex(opts...), func() { store.Close() }, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import "github.com/shamsup/pokedexcli/internal/termout"
+
+// typeColors are the colors the games have long used for each type.
+var typeColors = map[string]string{
+	"normal":   "#a8a878",
+	"fire":     "#f08030",
+	"water":    "#6890f0",
+	"electric": "#f8d030",
+	"grass":    "#78c850",
+	"ice":      "#98d8d8",
+	"fighting": "#c03028",
+	"poison":   "#a040a0",
+	"ground":   "#e0c068",
+	"flying":   "#a890f0",
+	"psychic":  "#f85888",
+	"bug":      "#a8b820",
+	"rock":     "#b8a038",
+	"ghost":    "#705898",
+	"dragon":   "#7038f8",
+	"dark":     "#705848",
+	"steel":    "#b8b8d0",
+	"fairy":    "#ee99ac",
+}
+
+const (
+	// headingStyle marks the headings of help and other long listings.
+	headingStyle = termout.Bold
+	// errorStyle marks errors printed by the REPL.
+	errorStyle = termout.Red
+	// caughtStyle marks a successful catch.
+	caughtStyle = termout.Green
+)
+
+// typeLabels returns types' display names in the chosen language, each in
+// its type's color.
+func (c *Config) typeLabels(names []string) []string {
+	labels := c.localizeAll(names, c.typeName)
+	for i, name := range names {
+		labels[i] = c.Out.Paint(labels[i], termout.Hex(typeColors[name]))
+	}
+	return labels
+}
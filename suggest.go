@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shamsup/pokedexcli/internal/fuzzy"
+	"github.com/shamsup/pokedexcli/pokeapi"
+)
+
+const maxSuggestions = 3
+
+// didYouMean returns a sentence suggesting the candidates closest to input,
+// or "" if none are close.
+func didYouMean(input string, candidates []string) string {
+	suggestions := fuzzy.Suggest(input, candidates, maxSuggestions)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" Did you mean %s?", strings.Join(suggestions, ", "))
+}
+
+// notFound replaces a PokeAPI 404 with a friendlier error that suggests
+// similar names. Other errors are returned unchanged.
+func notFound(err error, kind, name string, names func() ([]string, error)) error {
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		return err
+	}
+	candidates, listErr := names()
+	if listErr != nil {
+		// Suggestions are a nicety; report the lookup without them.
+		candidates = nil
+	}
+	return fmt.Errorf("no %s named %q.%s", kind, name, didYouMean(name, candidates))
+}
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shamsup/pokedexcli/internal/lineedit"
+	"github.com/shamsup/pokedexcli/internal/termout"
+	"github.com/shamsup/pokedexcli/pokeapi"
+	"github.com/shamsup/pokedexcli/pokedex"
+	"golang.org/x/term"
+)
+
+const (
+	// enterFullScreen switches to the terminal's alternate screen and hides
+	// the cursor; leaveFullScreen undoes it, leaving the shell as it was.
+	enterFullScreen = "\x1b[?1049h\x1b[?25l"
+	leaveFullScreen = "\x1b[?25h\x1b[?1049l"
+	// tuiCursor marks the selected line of the focused pane, and
+	// tuiBlurredCursor that of the others. Neither is a color, so both
+	// show with NO_COLOR.
+	tuiCursor        = "\x1b[7m"
+	tuiBlurredCursor = "\x1b[1m"
+	tuiReset         = "\x1b[0m"
+	// tuiSeparator goes between panes.
+	tuiSeparator = " │ "
+	// tuiMinWidth and tuiMinHeight are the smallest terminal the panes fit.
+	tuiMinWidth  = 60
+	tuiMinHeight = 8
+	// tuiCaught marks encounters already in the Pokedex.
+	tuiCaught = "✓"
+)
+
+// tuiPane is one of the TUI's panes, left to right.
+type tuiPane int
+
+const (
+	paneLocations tuiPane = iota
+	paneEncounters
+	paneCaught
+	paneCount
+)
+
+// tuiList is a pane's list with a cursor, scrolled to keep the cursor in
+// view.
+type tuiList struct {
+	// names are the items' API names and labels what is shown for them.
+	names  []string
+	labels []string
+	cursor int
+	offset int
+}
+
+// set replaces the items, keeping the cursor where it was if it still
+// fits.
+func (l *tuiList) set(names, labels []string) {
+	l.names, l.labels = names, labels
+	l.cursor = max(min(l.cursor, len(names)-1), 0)
+}
+
+func (l *tuiList) move(delta int) {
+	if len(l.names) > 0 {
+		l.cursor = max(min(l.cursor+delta, len(l.names)-1), 0)
+	}
+}
+
+func (l *tuiList) selected() string {
+	if l.cursor < len(l.names) {
+		return l.names[l.cursor]
+	}
+	return ""
+}
+
+// lines draws the visible part of the list, height lines of width cells.
+func (l *tuiList) lines(width, height int, focused bool) []string {
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	}
+	if l.cursor >= l.offset+height {
+		l.offset = l.cursor - height + 1
+	}
+	var lines []string
+	for i := l.offset; i < min(len(l.labels), l.offset+height); i++ {
+		line := termout.Pad(termout.Truncate(l.labels[i], width), width)
+		switch {
+		case i != l.cursor:
+		case focused:
+			line = tuiCursor + line + tuiReset
+		default:
+			line = tuiBlurredCursor + line + tuiReset
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// tui is the full-screen interface: pages of location areas, the
+// encounters in the one explored, and the caught Pokemon with their
+// details.
+type tui struct {
+	c   *Config
+	out io.Writer
+	// size returns the terminal's width and height.
+	size func() (int, int)
+
+	focus                         tuiPane
+	locations, encounters, caught tuiList
+	// page is the page of location areas shown, starting at the
+	// pageStart'th area.
+	page      pokeapi.PaginatedResponse[pokeapi.ListEntry]
+	pageStart int
+	// area is the explored location area, and encounterNotes how each of
+	// its Pokemon is met.
+	area           string
+	areaLabel      string
+	encounterNotes []string
+	// caughtEntries back the caught list; detail shows the selected one's
+	// details in place of the list.
+	caughtEntries []pokedex.Entry
+	detail        bool
+	status        string
+}
+
+func newTUI(c *Config, out io.Writer, size func() (int, int)) *tui {
+	return &tui{c: c, out: out, size: size}
+}
+
+// runTUI implements `pokedexcli tui`.
+func runTUI(c *Config) error {
+	if !lineedit.IsTerminal(os.Stdin) || !c.Out.TTY {
+		return errors.New("tui needs a terminal")
+	}
+	restore, err := lineedit.MakeRaw(os.Stdin)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	fmt.Fprint(c.Out, enterFullScreen)
+	defer fmt.Fprint(c.Out, leaveFullScreen)
+
+	t := newTUI(c, c.Out, func() (int, int) {
+		width, height, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil {
+			return 80, 24
+		}
+		return width, height
+	})
+	return t.run(os.Stdin)
+}
+
+// run draws the screen and handles keys from in until the user quits.
+func (t *tui) run(in io.Reader) error {
+	keys := bufio.NewReader(in)
+	t.loadLocations("", 0)
+	t.loadCaught()
+	for {
+		t.draw()
+		key, err := lineedit.ReadKey(keys)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if t.handle(key) {
+			return nil
+		}
+	}
+}
+
+// handle acts on a key and reports whether the user quit.
+func (t *tui) handle(key rune) bool {
+	list := t.focusedList()
+	switch key {
+	case 'q', lineedit.KeyCtrlC:
+		return true
+	case '\t', lineedit.KeyRight:
+		t.focus = (t.focus + 1) % paneCount
+	case lineedit.KeyBackTab, lineedit.KeyLeft:
+		t.focus = (t.focus + paneCount - 1) % paneCount
+	case '1', '2', '3':
+		t.focus = tuiPane(key - '1')
+	case lineedit.KeyUp, 'k':
+		list.move(-1)
+	case lineedit.KeyDown, 'j':
+		list.move(1)
+	case lineedit.KeyPageUp:
+		list.move(-t.bodyHeight())
+	case lineedit.KeyPageDown:
+		list.move(t.bodyHeight())
+	case lineedit.KeyHome, 'g':
+		list.move(-len(list.names))
+	case lineedit.KeyEnd, 'G':
+		list.move(len(list.names))
+	case 'n':
+		t.changePage(t.page.Next, 1)
+	case 'p':
+		t.changePage(t.page.Previous, -1)
+	case 'c':
+		if t.focus == paneEncounters {
+			t.catch()
+		}
+	case '\r', '\n':
+		switch t.focus {
+		case paneLocations:
+			t.explore()
+		case paneEncounters:
+			t.catch()
+		case paneCaught:
+			t.detail = !t.detail && len(t.caughtEntries) > 0
+		}
+	case lineedit.KeyEscape:
+		t.detail = false
+	}
+	return false
+}
+
+func (t *tui) focusedList() *tuiList {
+	switch t.focus {
+	case paneEncounters:
+		return &t.encounters
+	case paneCaught:
+		return &t.caught
+	}
+	return &t.locations
+}
+
+// fail shows err in the status line.
+func (t *tui) fail(err error) {
+	t.status = t.c.Out.Paint("Error:", errorStyle) + " " + err.Error()
+}
+
+// loading shows what's being fetched while the user waits.
+func (t *tui) loading(what string) {
+	t.status = "Loading " + what + "..."
+	t.draw()
+}
+
+// loadLocations shows the page of location areas at url, or the first
+// page if url is empty.
+func (t *tui) loadLocations(url string, start int) {
+	t.loading("locations")
+	page, err := pokeapi.GetLocations(url)
+	if err != nil {
+		t.fail(err)
+		return
+	}
+	names := entryNames(page.Results)
+	labels := t.c.localizeAll(names, t.c.areaName)
+	for i, name := range names {
+		labels[i] = withAPIName(labels[i], name)
+	}
+	t.page, t.pageStart = page, start
+	t.locations = tuiList{}
+	t.locations.set(names, labels)
+	t.status = ""
+}
+
+// changePage moves to the next (step 1) or previous (step -1) page of
+// location areas.
+func (t *tui) changePage(url *string, step int) {
+	switch {
+	case url == nil && step > 0:
+		t.status = "You're on the last page."
+	case url == nil:
+		t.status = "You're on the first page."
+	case step > 0:
+		t.loadLocations(*url, t.pageStart+len(t.page.Results))
+	default:
+		// Pages are the same size, so the previous one is as long as this.
+		t.loadLocations(*url, max(t.pageStart-len(t.page.Results), 0))
+	}
+}
+
+// explore lists the Pokemon met in the selected area, in the selected
+// game if there is one.
+func (t *tui) explore() {
+	area := t.locations.selected()
+	if area == "" {
+		return
+	}
+	t.loading(area)
+	details, err := pokeapi.GetLocationDetails(area)
+	if err != nil {
+		t.fail(err)
+		return
+	}
+	version := t.c.Profile.Version
+	var names, notes []string
+	for _, encounter := range details.PokemonEncounters {
+		rows := summarizeEncounters(encounter.Pokemon.Name, encounter.VersionDetails, version, "")
+		if len(rows) == 0 {
+			continue
+		}
+		names = append(names, encounter.Pokemon.Name)
+		notes = append(notes, encounterNote(rows))
+	}
+	t.area, t.areaLabel = area, t.locations.labels[t.locations.cursor]
+	t.encounterNotes = notes
+	t.encounters = tuiList{}
+	t.encounters.set(names, t.encounterLabels(names))
+	t.focus = paneEncounters
+	switch {
+	case len(names) > 0:
+		t.status = fmt.Sprintf("Found %d Pokemon. Press Enter to throw a Pokeball.", len(names))
+	case version != "":
+		t.status = "No Pokemon are found here in " + version + "."
+	default:
+		t.status = "No Pokemon are found here."
+	}
+}
+
+// encounterNote sums up how a Pokemon is met, e.g. "surf, good-rod Lv 15-30".
+func encounterNote(rows []encounterRow) string {
+	var methods []string
+	low, high := rows[0].MinLevel, rows[0].MaxLevel
+	for _, row := range rows {
+		if !slices.Contains(methods, row.Method) {
+			methods = append(methods, row.Method)
+		}
+		low, high = min(low, row.MinLevel), max(high, row.MaxLevel)
+	}
+	levels := fmt.Sprintf("Lv %d-%d", low, high)
+	if low == high {
+		levels = fmt.Sprintf("Lv %d", low)
+	}
+	return strings.Join(methods, ", ") + " " + levels
+}
+
+// encounterLabels shows each encounter with how it's met, ticking those
+// already caught.
+func (t *tui) encounterLabels(names []string) []string {
+	labels := t.c.localizeAll(names, t.c.pokemonName)
+	for i, name := range names {
+		mark := " "
+		if t.c.Pokedex.SeenPokemon(name) {
+			mark = tuiCaught
+		}
+		labels[i] = fmt.Sprintf("%s %s  %s", mark, labels[i], t.encounterNotes[i])
+	}
+	return labels
+}
+
+// catch throws a Pokeball at the selected encounter.
+func (t *tui) catch() {
+	name := t.encounters.selected()
+	if name == "" {
+		return
+	}
+	display := t.c.pokemonName(name)
+	t.status = "Throwing a Pokeball at " + display + "..."
+	t.draw()
+	_, caught, err := t.c.Pokedex.CatchPokemon(name)
+	if err != nil {
+		t.fail(err)
+		return
+	}
+	if caught {
+		t.status = t.c.Out.Paint(display+" was caught!", caughtStyle)
+	} else {
+		t.status = display + " got away..."
+	}
+	t.encounters.set(t.encounters.names, t.encounterLabels(t.encounters.names))
+	t.loadCaught()
+}
+
+// loadCaught lists the caught Pokemon in Pokedex order.
+func (t *tui) loadCaught() {
+	entries, err := t.c.Pokedex.Query(pokedex.Query{Sort: pokedex.SortByID})
+	if err != nil {
+		t.fail(err)
+		return
+	}
+	names := make([]string, len(entries))
+	labels := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Pokemon.Name
+		labels[i] = fmt.Sprintf("#%03d %s", entry.Pokemon.ID, t.c.pokemonName(entry.Pokemon.Name))
+	}
+	t.caughtEntries = entries
+	t.caught.set(names, labels)
+}
+
+// detailLines describes the selected caught Pokemon.
+func (t *tui) detailLines() []string {
+	if t.caught.cursor >= len(t.caughtEntries) {
+		return nil
+	}
+	entry := t.caughtEntries[t.caught.cursor]
+	r := pokedex.NewRecord(entry)
+	throws := fmt.Sprintf("%d throws", r.Attempts)
+	if r.Attempts == 1 {
+		throws = "1 throw"
+	}
+	lines := []string{
+		t.c.Out.Paint(fmt.Sprintf("#%03d %s", r.ID, t.c.pokemonName(r.Name)), headingStyle),
+		"Types: " + strings.Join(t.c.typeLabels(t.c.typeNames(entry.Pokemon)), "/"),
+		fmt.Sprintf("Height: %.1f m  Weight: %.1f kg", r.Height, r.Weight),
+		"Abilities: " + strings.Join(r.Abilities, ", "),
+		fmt.Sprintf("Caught: %s, %s", r.CaughtAt.Format(time.DateOnly), throws),
+		"",
+	}
+	for _, stat := range entry.Pokemon.Stats {
+		bar := t.c.Out.Paint(statBar(stat.BaseStat), statColor(stat.BaseStat))
+		lines = append(lines, fmt.Sprintf("%-7s %3d %s", statLabel(stat.Stat.Name), stat.BaseStat, bar))
+	}
+	return append(lines, fmt.Sprintf("%-7s %3d", "Total", r.BST))
+}
+
+// bodyHeight is how many lines the panes have below their titles, leaving
+// room for the status and key help.
+func (t *tui) bodyHeight() int {
+	_, height := t.size()
+	return max(height-3, 1)
+}
+
+// keyHelp lists the keys that do something in the focused pane.
+func (t *tui) keyHelp() string {
+	switch {
+	case t.focus == paneLocations:
+		return "↑↓ move  Enter explore  n/p page  Tab next pane  q quit"
+	case t.focus == paneEncounters:
+		return "↑↓ move  Enter catch  n/p page  Tab next pane  q quit"
+	case t.detail:
+		return "↑↓ move  Enter/Esc back to list  Tab next pane  q quit"
+	}
+	return "↑↓ move  Enter details  Tab next pane  q quit"
+}
+
+// draw redraws the whole screen.
+func (t *tui) draw() {
+	width, height := t.size()
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	if width < tuiMinWidth || height < tuiMinHeight {
+		b.WriteString(termout.Truncate("Make the terminal bigger to see the Pokedex.", width))
+		b.WriteString("\x1b[J")
+		fmt.Fprint(t.out, b.String())
+		return
+	}
+
+	paneWidth := (width - 2*termout.Width(tuiSeparator)) / int(paneCount)
+	body := t.bodyHeight()
+	titles := []string{
+		fmt.Sprintf("Locations %d-%d of %d", t.pageStart+1, t.pageStart+len(t.page.Results), t.page.Count),
+		"Encounters",
+		fmt.Sprintf("Caught (%d)", len(t.caughtEntries)),
+	}
+	if t.area != "" {
+		titles[paneEncounters] += ": " + t.areaLabel
+	}
+	panes := [][]string{
+		t.locations.lines(paneWidth, body, t.focus == paneLocations),
+		t.encounters.lines(paneWidth, body, t.focus == paneEncounters),
+		t.caught.lines(paneWidth, body, t.focus == paneCaught),
+	}
+	if t.detail {
+		panes[paneCaught] = t.detailLines()
+	}
+
+	row := func(cells []string) {
+		for i, cell := range cells {
+			if i > 0 {
+				b.WriteString(tuiSeparator)
+			}
+			b.WriteString(termout.Pad(termout.Truncate(cell, paneWidth), paneWidth))
+		}
+		b.WriteString("\x1b[K\r\n")
+	}
+	for i, title := range titles {
+		title = termout.Truncate(title, paneWidth)
+		if tuiPane(i) == t.focus {
+			title = "\x1b[1;4m" + title + tuiReset
+		}
+		titles[i] = title
+	}
+	row(titles)
+	for line := range body {
+		cells := make([]string, len(panes))
+		for i, pane := range panes {
+			if line < len(pane) {
+				cells[i] = pane[line]
+			}
+		}
+		row(cells)
+	}
+	b.WriteString(termout.Truncate(t.status, width) + "\x1b[K\r\n")
+	b.WriteString(t.c.Out.Paint(termout.Truncate(t.keyHelp(), width), termout.Dim) + "\x1b[K\x1b[J")
+	fmt.Fprint(t.out, b.String())
+}
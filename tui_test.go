@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// runTUIKeys runs a TUI on the fixture server, pressing keys, and returns
+// it with the last screen it drew.
+func runTUIKeys(t *testing.T, r *testREPL, keys string) (*tui, string) {
+	t.Helper()
+	var out bytes.Buffer
+	ui := newTUI(r.c, &out, func() (int, int) { return 150, 24 })
+	if err := ui.run(strings.NewReader(keys)); err != nil {
+		t.Fatal(err)
+	}
+	frames := strings.Split(out.String(), "\x1b[H")
+	return ui, frames[len(frames)-1]
+}
+
+func TestTUI(t *testing.T) {
+	r := newTestREPL(t)
+
+	// Explore the first area, try the first encounter, which has no
+	// Pokemon to catch, then move down to magikarp and catch it.
+	ui, screen := runTUIKeys(t, r, "\r\rjjjc")
+	if ui.area != "canalave-city-area" || ui.focus != paneEncounters {
+		t.Fatalf("expected to be exploring canalave-city-area, got %q in pane %d", ui.area, ui.focus)
+	}
+	for _, want := range []string{
+		"Locations 1-20 of ",
+		"Encounters: canalave-city-area",
+		"Caught (1)",
+		"\x1b[7m✓ magikarp  old-rod, good-rod Lv 3-25",
+		"#129 magikarp",
+		"magikarp was caught!",
+	} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("expected %q on screen, got %q", want, screen)
+		}
+	}
+	if caught, _ := r.c.Pokedex.ListCaughtPokemon(); len(caught) != 1 || caught[0] != "magikarp" {
+		t.Errorf("expected magikarp in the Pokedex, got %v", caught)
+	}
+
+	// Page through the locations, then open magikarp's details.
+	ui, screen = runTUIKeys(t, r, "npn\t\t\r")
+	if ui.pageStart != 20 || ui.locations.selected() != "mt-coronet-1f-route-216" {
+		t.Errorf("expected the second page, got %d starting at %q", ui.pageStart, ui.locations.selected())
+	}
+	for _, want := range []string{"Locations 21-40 of ", "Types: water", "Height: 0.9 m", "Caught: ", "Speed    80 ", "Total   200"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("expected %q on screen, got %q", want, screen)
+		}
+	}
+
+	if _, screen = runTUIKeys(t, r, "p"); !strings.Contains(screen, "You're on the first page.") {
+		t.Errorf("expected a first page message, got %q", screen)
+	}
+}